@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"go-react-backend/models"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// SessionTTL is how long a login session stays valid
+const SessionTTL = 7 * 24 * time.Hour
+
+// MinPasswordLength is the shortest password accepted for new accounts
+const MinPasswordLength = 8
+
+// usernamePattern restricts usernames to characters that are safe in URLs
+// and logs
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]{3,32}$`)
+
+// dummyHash is compared against when a username does not exist, so failed
+// logins take the same time whether or not the account exists
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not-a-real-password"), bcrypt.DefaultCost)
+
+// Service handles user accounts, password checks, login sessions and API
+// tokens
+type Service struct {
+	users    models.UserStore
+	sessions models.SessionStore
+	tokens   models.APITokenStore
+}
+
+// NewService creates an auth service on top of the given stores
+func NewService(users models.UserStore, sessions models.SessionStore, tokens models.APITokenStore) *Service {
+	return &Service{users: users, sessions: sessions, tokens: tokens}
+}
+
+// CreateUser validates the username and password and stores a new account
+// with a bcrypt hash of the password
+func (s *Service) CreateUser(username string, password string, role string) (models.User, error) {
+	if !usernamePattern.MatchString(username) {
+		return models.User{}, &models.ValidationError{
+			Field:   "username",
+			Message: "Username must be 3-32 letters, digits, dots, dashes or underscores",
+		}
+	}
+	if !models.ValidRole(role) {
+		return models.User{}, &models.ValidationError{Field: "role", Message: "Unknown role: " + role}
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return s.users.CreateUser(models.User{
+		Username:     username,
+		PasswordHash: hash,
+		Role:         role,
+	})
+}
+
+// Login checks a username and password and starts a new session, returning
+// the session token to hand to the client
+func (s *Service) Login(username string, password string) (string, *models.Session, *models.User, error) {
+	user, err := s.users.GetUserByUsername(username)
+	if err != nil {
+		if err.Error() != "user not found" {
+			return "", nil, nil, err
+		}
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return "", nil, nil, errors.New("invalid credentials")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return "", nil, nil, errors.New("invalid credentials")
+	}
+
+	// Logins are rare enough to double as the cleanup trigger for old sessions
+	if _, err := s.sessions.DeleteExpiredSessions(time.Now()); err != nil {
+		fmt.Printf("❌ Failed to delete expired sessions: %v\n", err)
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	now := time.Now()
+	session := models.Session{
+		TokenHash: HashToken(token),
+		UserID:    user.ID,
+		Created:   now,
+		Expires:   now.Add(SessionTTL),
+	}
+	if err := s.sessions.CreateSession(session); err != nil {
+		return "", nil, nil, err
+	}
+
+	return token, &session, user, nil
+}
+
+// Logout ends the session identified by token
+func (s *Service) Logout(token string) error {
+	return s.sessions.DeleteSession(HashToken(token))
+}
+
+// Authenticate returns the user owning a session token
+func (s *Service) Authenticate(token string) (*models.User, error) {
+	session, err := s.sessions.GetSession(HashToken(token))
+	if err != nil {
+		if err.Error() == "session not found" {
+			return nil, errors.New("unauthenticated")
+		}
+		return nil, err
+	}
+	if !session.Expires.After(time.Now()) {
+		s.sessions.DeleteSession(session.TokenHash)
+		return nil, errors.New("unauthenticated")
+	}
+
+	user, err := s.users.GetUserByID(session.UserID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, errors.New("unauthenticated")
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// hashPassword checks a new password's length and returns its bcrypt hash
+func hashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLength {
+		return "", &models.ValidationError{
+			Field:   "password",
+			Message: fmt.Sprintf("Password must be at least %d characters", MinPasswordLength),
+		}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// newToken returns 32 random bytes, hex encoded
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// HashToken returns the SHA-256 of a token, as stored in place of the token
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
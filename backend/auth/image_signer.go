@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+// ImageSigner signs image transformation URLs, so the server only does the
+// resizing and cropping it handed out links for. Signatures are an
+// HMAC-SHA256 of the media asset's hash and the transformation parameters.
+type ImageSigner struct {
+	secret []byte
+}
+
+// NewImageSigner creates a signer using secret, which must be at least
+// MinPreviewSecretLength bytes
+func NewImageSigner(secret []byte) (*ImageSigner, error) {
+	if len(secret) < MinPreviewSecretLength {
+		return nil, errors.New("image signing secret is too short")
+	}
+	return &ImageSigner{secret: secret}, nil
+}
+
+// Sign returns the signature for transforming the asset with hash as params
+// describe
+func (s *ImageSigner) Sign(hash string, params string) string {
+	return base64.RawURLEncoding.EncodeToString(s.mac(hash, params))
+}
+
+// Verify checks that signature was issued for hash and params
+func (s *ImageSigner) Verify(hash string, params string, signature string) error {
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, s.mac(hash, params)) {
+		return errors.New("invalid image signature")
+	}
+	return nil
+}
+
+// mac signs an asset hash and transformation parameters
+func (s *ImageSigner) mac(hash string, params string) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte("transform\x00" + hash + "\x00" + params))
+	return h.Sum(nil)
+}
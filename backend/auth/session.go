@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"go-react-backend/models"
+)
+
+// SessionCookieName is the cookie carrying the session token
+const SessionCookieName = "blog_session"
+
+// contextKey is the type of values this package stores in request contexts
+type contextKey int
+
+const (
+	userContextKey contextKey = iota
+	tokenContextKey
+)
+
+// WithUser returns a copy of ctx carrying the authenticated user
+func WithUser(ctx context.Context, user *models.User) context.Context {
+	return context.WithValue(ctx, userContextKey, user)
+}
+
+// UserFromContext returns the authenticated user stored by WithUser, if any
+func UserFromContext(ctx context.Context) (*models.User, bool) {
+	user, ok := ctx.Value(userContextKey).(*models.User)
+	return user, ok && user != nil
+}
+
+// WithAPIToken returns a copy of ctx recording that the request was
+// authenticated with an API token rather than a session
+func WithAPIToken(ctx context.Context, token *models.APIToken) context.Context {
+	return context.WithValue(ctx, tokenContextKey, token)
+}
+
+// APITokenFromContext returns the API token stored by WithAPIToken, if any
+func APITokenFromContext(ctx context.Context) (*models.APIToken, bool) {
+	token, ok := ctx.Value(tokenContextKey).(*models.APIToken)
+	return token, ok && token != nil
+}
+
+// HasScope reports whether the request's credentials grant scope. Sessions
+// grant every scope; API tokens only the scopes they were created with.
+func HasScope(ctx context.Context, scope string) bool {
+	if token, ok := APITokenFromContext(ctx); ok {
+		return token.HasScope(scope)
+	}
+	_, ok := UserFromContext(ctx)
+	return ok
+}
+
+// UserFromRequest returns the user owning the request's session cookie
+func (s *Service) UserFromRequest(r *http.Request) (*models.User, error) {
+	cookie, err := r.Cookie(SessionCookieName)
+	if err != nil || cookie.Value == "" {
+		return nil, errors.New("unauthenticated")
+	}
+	return s.Authenticate(cookie.Value)
+}
+
+// isSecureRequest reports whether the client reached us over HTTPS, either
+// directly or through a TLS-terminating proxy
+func isSecureRequest(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
+
+// SetSessionCookie sends the session token to the client
+func SetSessionCookie(w http.ResponseWriter, r *http.Request, token string, expires time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  expires,
+		MaxAge:   int(time.Until(expires).Seconds()),
+		HttpOnly: true,
+		Secure:   isSecureRequest(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// ClearSessionCookie removes the session cookie from the client
+func ClearSessionCookie(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   isSecureRequest(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-react-backend/models"
+
+	"github.com/google/uuid"
+)
+
+// APITokenPrefix starts every API token, so leaked tokens are easy to spot
+const APITokenPrefix = "blog_"
+
+// lastUsedResolution limits how often a token's last-used time is written
+// back, so busy automation doesn't rewrite the token file on every request
+const lastUsedResolution = time.Minute
+
+// CreateAPIToken creates a token for user with the given name and scopes,
+// returning the token itself, which is not stored and can't be shown again
+func (s *Service) CreateAPIToken(user *models.User, name string, scopes []string) (string, models.APIToken, error) {
+	secret, err := newToken()
+	if err != nil {
+		return "", models.APIToken{}, err
+	}
+	token := APITokenPrefix + secret
+
+	created, err := s.tokens.CreateAPIToken(models.APIToken{
+		UserID:    user.ID,
+		Name:      name,
+		Prefix:    token[:len(APITokenPrefix)+8],
+		TokenHash: HashToken(token),
+		Scopes:    scopes,
+	})
+	if err != nil {
+		return "", models.APIToken{}, err
+	}
+
+	return token, created, nil
+}
+
+// ListAPITokens returns a user's API tokens
+func (s *Service) ListAPITokens(userID uuid.UUID) ([]models.APIToken, error) {
+	return s.tokens.GetAPITokensByUser(userID)
+}
+
+// RevokeAPIToken deletes one of a user's API tokens
+func (s *Service) RevokeAPIToken(userID uuid.UUID, id uuid.UUID) error {
+	return s.tokens.DeleteAPIToken(userID, id)
+}
+
+// AuthenticateAPIToken returns the token record and owning user for a
+// bearer token, recording when it was used
+func (s *Service) AuthenticateAPIToken(token string) (*models.User, *models.APIToken, error) {
+	if !strings.HasPrefix(token, APITokenPrefix) {
+		return nil, nil, errors.New("unauthenticated")
+	}
+
+	apiToken, err := s.tokens.GetAPITokenByHash(HashToken(token))
+	if err != nil {
+		if err.Error() == "token not found" {
+			return nil, nil, errors.New("unauthenticated")
+		}
+		return nil, nil, err
+	}
+
+	user, err := s.users.GetUserByID(apiToken.UserID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, nil, errors.New("unauthenticated")
+		}
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if apiToken.LastUsed == nil || now.Sub(*apiToken.LastUsed) >= lastUsedResolution {
+		if err := s.tokens.TouchAPIToken(apiToken.ID, now); err != nil {
+			fmt.Printf("❌ Failed to record API token use: %v\n", err)
+		}
+		apiToken.LastUsed = &now
+	}
+
+	return user, apiToken, nil
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"errors"
+
+	"go-react-backend/models"
+
+	"github.com/google/uuid"
+)
+
+// ListUsers returns every user account
+func (s *Service) ListUsers() ([]models.User, error) {
+	return s.users.GetAllUsers()
+}
+
+// UpdateUser changes a user's role and/or password
+func (s *Service) UpdateUser(id uuid.UUID, updates models.UpdateUserRequest) (*models.User, error) {
+	user, err := s.users.GetUserByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if updates.Role != nil {
+		if !models.ValidRole(*updates.Role) {
+			return nil, &models.ValidationError{Field: "role", Message: "Unknown role: " + *updates.Role}
+		}
+		if user.Role == models.RoleAdmin && *updates.Role != models.RoleAdmin {
+			if err := s.ensureOtherAdmin(user.ID); err != nil {
+				return nil, err
+			}
+		}
+		user.Role = *updates.Role
+	}
+
+	if updates.Password != nil {
+		hash, err := hashPassword(*updates.Password)
+		if err != nil {
+			return nil, err
+		}
+		user.PasswordHash = hash
+	}
+
+	if err := s.users.UpdateUser(*user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// DeleteUser removes a user account along with its sessions and API tokens
+func (s *Service) DeleteUser(id uuid.UUID) error {
+	user, err := s.users.GetUserByID(id)
+	if err != nil {
+		return err
+	}
+
+	if user.Role == models.RoleAdmin {
+		if err := s.ensureOtherAdmin(user.ID); err != nil {
+			return err
+		}
+	}
+
+	return s.users.DeleteUser(id)
+}
+
+// ensureOtherAdmin fails unless some admin other than id exists, so the
+// last admin can't be demoted or deleted
+func (s *Service) ensureOtherAdmin(id uuid.UUID) error {
+	users, err := s.users.GetAllUsers()
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if user.Role == models.RoleAdmin && user.ID != id {
+			return nil
+		}
+	}
+
+	return errors.New("cannot remove the last admin")
+}
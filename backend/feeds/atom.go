@@ -0,0 +1,113 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+)
+
+// atomFeed is an Atom 1.0 (RFC 4287) document
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Links    []atomLink  `xml:"link"`
+	Updated  string      `xml:"updated"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length int    `xml:"length,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Links      []atomLink     `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Author     atomAuthor     `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Summary    string         `xml:"summary"`
+	Content    *atomContent   `xml:"content,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Atom serves the Atom feed
+func (h *Handler) Atom(w http.ResponseWriter, r *http.Request) {
+	f, err := h.buildFeed(r, "/atom.xml")
+	if err != nil {
+		serveFeedError(w, err)
+		return
+	}
+
+	// Atom requires an updated time even for an empty feed
+	updated := f.updated
+	if updated.IsZero() {
+		updated = time.Unix(0, 0)
+	}
+
+	doc := atomFeed{
+		Title:    f.title,
+		Subtitle: f.description,
+		ID:       f.siteURL,
+		Links: []atomLink{
+			{Href: f.siteURL, Rel: "alternate", Type: "text/html"},
+			{Href: f.feedURL, Rel: "self", Type: "application/atom+xml"},
+		},
+		Updated: updated.UTC().Format(time.RFC3339),
+	}
+
+	for _, e := range f.entries {
+		author := e.blog.AuthorName
+		if author == "" {
+			author = e.blog.AuthorUsername
+		}
+
+		entry := atomEntry{
+			Title:     e.blog.Title,
+			ID:        "urn:uuid:" + e.blog.ID.String(),
+			Links:     []atomLink{{Href: e.url, Rel: "alternate", Type: "text/html"}},
+			Published: e.blog.Created.UTC().Format(time.RFC3339),
+			Updated:   e.blog.Updated.UTC().Format(time.RFC3339),
+			Author:    atomAuthor{Name: author},
+			Summary:   summary(e.blog),
+		}
+		for _, category := range categories(e.blog) {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		if f.full {
+			entry.Content = &atomContent{Type: "text", Value: e.blog.Content}
+		}
+		if e.image != nil {
+			entry.Links = append(entry.Links, atomLink{
+				Href: e.image.url, Rel: "enclosure", Type: e.image.mimeType, Length: e.image.length,
+			})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		serveFeedError(w, err)
+		return
+	}
+
+	serveFeed(w, r, "application/atom+xml; charset=utf-8", append([]byte(xml.Header), body...), f.updated)
+}
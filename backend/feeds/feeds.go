@@ -0,0 +1,209 @@
+package feeds
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"go-react-backend/models"
+)
+
+// DefaultLimit is how many of the newest posts a feed lists
+const DefaultLimit = 20
+
+// Config describes the site the feeds are published for
+type Config struct {
+	Title       string
+	Description string
+	Limit       int // number of posts per feed, DefaultLimit if zero
+}
+
+// Handler serves RSS, Atom and JSON feeds of the published blogs in a store
+type Handler struct {
+	store  models.BlogStore
+	config Config
+}
+
+// NewHandler creates a feed handler for store
+func NewHandler(store models.BlogStore, config Config) *Handler {
+	if config.Limit <= 0 {
+		config.Limit = DefaultLimit
+	}
+	return &Handler{store: store, config: config}
+}
+
+// feed is the format-independent content of a feed
+type feed struct {
+	title       string
+	description string
+	siteURL     string
+	feedURL     string
+	updated     time.Time
+	full        bool // include full post content, not just summaries
+	entries     []entry
+}
+
+// entry is a published blog prepared for a feed
+type entry struct {
+	blog  models.Blog
+	url   string
+	image *enclosure
+}
+
+// enclosure describes a post's image as a feed attachment
+type enclosure struct {
+	url      string
+	mimeType string
+	length   int
+}
+
+// BaseURL returns the absolute URL of the site as the client reached it
+func BaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// buildFeed collects the newest published blogs for the feed at path. The
+// content query parameter selects "full" (default) or "summary" entries; tag
+// and category limit the feed to matching blogs.
+func (h *Handler) buildFeed(r *http.Request, path string) (*feed, error) {
+	blogs, err := h.store.GetAllBlogs()
+	if err != nil {
+		return nil, err
+	}
+
+	params := r.URL.Query()
+	var tag string
+	if tags := models.NormalizeTags([]string{params.Get("tag")}); len(tags) > 0 {
+		tag = tags[0]
+	}
+	category := models.NormalizeCategory(params.Get("category"))
+
+	now := time.Now()
+	published := make([]models.Blog, 0, len(blogs))
+	for _, blog := range blogs {
+		if !blog.IsLive(now) {
+			continue
+		}
+		if tag != "" && !blog.HasTag(tag) {
+			continue
+		}
+		if category != "" && !blog.InCategory(category) {
+			continue
+		}
+		published = append(published, blog)
+	}
+	sort.SliceStable(published, func(i, j int) bool {
+		return published[i].Created.After(published[j].Created)
+	})
+	if len(published) > h.config.Limit {
+		published = published[:h.config.Limit]
+	}
+
+	baseURL := BaseURL(r)
+	f := &feed{
+		title:       h.config.Title,
+		description: h.config.Description,
+		siteURL:     baseURL + "/",
+		feedURL:     baseURL + path,
+		full:        params.Get("content") != "summary",
+	}
+
+	// The self link keeps the options that shaped this feed
+	selfParams := url.Values{}
+	if !f.full {
+		selfParams.Set("content", "summary")
+	}
+	if tag != "" {
+		selfParams.Set("tag", tag)
+		f.title += " - #" + tag
+	}
+	if category != "" {
+		selfParams.Set("category", category)
+		f.title += " - " + category
+	}
+	if len(selfParams) > 0 {
+		f.feedURL += "?" + selfParams.Encode()
+	}
+
+	for _, blog := range published {
+		if blog.Updated.After(f.updated) {
+			f.updated = blog.Updated
+		}
+
+		e := entry{
+			blog: blog,
+			url:  baseURL + "/blogs/" + url.PathEscape(blog.Slug),
+		}
+		if blog.Image != "" {
+			// The length is required by RSS; a missing file drops the enclosure
+			if data, err := h.store.GetBlogImage(blog.Slug, blog.Image); err == nil {
+				e.image = &enclosure{
+					url:      baseURL + "/api/images/" + url.PathEscape(blog.Slug) + "/" + url.PathEscape(blog.Image),
+					mimeType: imageMimeType(blog),
+					length:   len(data),
+				}
+			}
+		}
+		f.entries = append(f.entries, e)
+	}
+
+	return f, nil
+}
+
+// imageMimeType returns the recorded MIME type of a blog's image, or guesses
+// it from the filename for images stored before types were recorded
+func imageMimeType(blog models.Blog) string {
+	if blog.ImageMimeType != "" {
+		return blog.ImageMimeType
+	}
+	if mimeType := mime.TypeByExtension(filepath.Ext(blog.Image)); mimeType != "" {
+		return mimeType
+	}
+	return "application/octet-stream"
+}
+
+// summary returns the short description of a post used when full content
+// isn't wanted
+func summary(blog models.Blog) string {
+	if blog.MetaDescription != "" {
+		return blog.MetaDescription
+	}
+	return blog.Title
+}
+
+// categories returns a blog's category followed by its tags, the terms feeds
+// list for an entry
+func categories(blog models.Blog) []string {
+	var terms []string
+	if blog.Category != "" {
+		terms = append(terms, blog.Category)
+	}
+	return append(terms, blog.Tags...)
+}
+
+// serveFeed writes a rendered feed with caching headers. ServeContent answers
+// If-None-Match and If-Modified-Since with 304 Not Modified.
+func serveFeed(w http.ResponseWriter, r *http.Request, contentType string, body []byte, updated time.Time) {
+	sum := sha256.Sum256(body)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	http.ServeContent(w, r, "", updated, bytes.NewReader(body))
+}
+
+// serveFeedError reports a failure to build a feed
+func serveFeedError(w http.ResponseWriter, err error) {
+	fmt.Printf("❌ Failed to build feed: %v\n", err)
+	http.Error(w, "Failed to build feed", http.StatusInternalServerError)
+}
@@ -0,0 +1,97 @@
+package feeds
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// jsonFeed is a JSON Feed 1.1 document (https://jsonfeed.org/version/1.1)
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description,omitempty"`
+	Language    string         `json:"language"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string               `json:"id"`
+	URL           string               `json:"url"`
+	Title         string               `json:"title"`
+	ContentText   string               `json:"content_text,omitempty"`
+	Summary       string               `json:"summary"`
+	Image         string               `json:"image,omitempty"`
+	DatePublished string               `json:"date_published"`
+	DateModified  string               `json:"date_modified"`
+	Authors       []jsonFeedAuthor     `json:"authors,omitempty"`
+	Tags          []string             `json:"tags,omitempty"`
+	Attachments   []jsonFeedAttachment `json:"attachments,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedAttachment struct {
+	URL         string `json:"url"`
+	MimeType    string `json:"mime_type"`
+	SizeInBytes int    `json:"size_in_bytes"`
+}
+
+// JSON serves the JSON Feed
+func (h *Handler) JSON(w http.ResponseWriter, r *http.Request) {
+	f, err := h.buildFeed(r, "/feed.json")
+	if err != nil {
+		serveFeedError(w, err)
+		return
+	}
+
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.title,
+		HomePageURL: f.siteURL,
+		FeedURL:     f.feedURL,
+		Description: f.description,
+		Language:    "en",
+		Items:       []jsonFeedItem{},
+	}
+
+	for _, e := range f.entries {
+		item := jsonFeedItem{
+			ID:            e.blog.ID.String(),
+			URL:           e.url,
+			Title:         e.blog.Title,
+			Summary:       summary(e.blog),
+			DatePublished: e.blog.Created.UTC().Format(time.RFC3339),
+			DateModified:  e.blog.Updated.UTC().Format(time.RFC3339),
+			Tags:          categories(e.blog),
+		}
+		if f.full {
+			item.ContentText = e.blog.Content
+		} else {
+			// JSON Feed items need content; the summary stands in for it
+			item.ContentText = item.Summary
+		}
+		if e.blog.AuthorName != "" {
+			item.Authors = []jsonFeedAuthor{{Name: e.blog.AuthorName}}
+		}
+		if e.image != nil {
+			item.Image = e.image.url
+			item.Attachments = []jsonFeedAttachment{{
+				URL: e.image.url, MimeType: e.image.mimeType, SizeInBytes: e.image.length,
+			}}
+		}
+		doc.Items = append(doc.Items, item)
+	}
+
+	body, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		serveFeedError(w, err)
+		return
+	}
+
+	serveFeed(w, r, "application/feed+json; charset=utf-8", body, f.updated)
+}
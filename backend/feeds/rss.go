@@ -0,0 +1,114 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+)
+
+// rssFeed is an RSS 2.0 document, with the content and Dublin Core modules
+// for full post bodies and author names
+type rssFeed struct {
+	XMLName      xml.Name   `xml:"rss"`
+	Version      string     `xml:"version,attr"`
+	XMLNSAtom    string     `xml:"xmlns:atom,attr"`
+	XMLNSContent string     `xml:"xmlns:content,attr"`
+	XMLNSDC      string     `xml:"xmlns:dc,attr"`
+	Channel      rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	AtomLink      rssLink   `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	GUID        rssGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Creator     string        `xml:"dc:creator,omitempty"`
+	Categories  []string      `xml:"category"`
+	Description string        `xml:"description"`
+	Content     *rssCDATA     `xml:"content:encoded,omitempty"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssCDATA struct {
+	Value string `xml:",cdata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int    `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// RSS serves the RSS 2.0 feed
+func (h *Handler) RSS(w http.ResponseWriter, r *http.Request) {
+	f, err := h.buildFeed(r, "/feed.xml")
+	if err != nil {
+		serveFeedError(w, err)
+		return
+	}
+
+	doc := rssFeed{
+		Version:      "2.0",
+		XMLNSAtom:    "http://www.w3.org/2005/Atom",
+		XMLNSContent: "http://purl.org/rss/1.0/modules/content/",
+		XMLNSDC:      "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:       f.title,
+			Link:        f.siteURL,
+			Description: f.description,
+			Language:    "en",
+			AtomLink:    rssLink{Href: f.feedURL, Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+	if !f.updated.IsZero() {
+		doc.Channel.LastBuildDate = f.updated.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, e := range f.entries {
+		item := rssItem{
+			Title:       e.blog.Title,
+			Link:        e.url,
+			GUID:        rssGUID{IsPermaLink: false, Value: "urn:uuid:" + e.blog.ID.String()},
+			PubDate:     e.blog.Created.UTC().Format(time.RFC1123Z),
+			Creator:     e.blog.AuthorName,
+			Categories:  categories(e.blog),
+			Description: summary(e.blog),
+		}
+		if f.full {
+			item.Content = &rssCDATA{Value: e.blog.Content}
+		}
+		if e.image != nil {
+			item.Enclosure = &rssEnclosure{URL: e.image.url, Length: e.image.length, Type: e.image.mimeType}
+		}
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		serveFeedError(w, err)
+		return
+	}
+
+	serveFeed(w, r, "application/rss+xml; charset=utf-8", append([]byte(xml.Header), body...), f.updated)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go-react-backend/auth"
+	"go-react-backend/models"
+)
+
+// AuthHandler handles login, logout and session HTTP requests
+type AuthHandler struct {
+	auth *auth.Service
+}
+
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(authService *auth.Service) *AuthHandler {
+	return &AuthHandler{auth: authService}
+}
+
+// Login checks the posted credentials and starts a session cookie
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req models.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.SendError(w, http.StatusBadRequest, "Invalid JSON", err.Error())
+		return
+	}
+
+	token, session, user, err := h.auth.Login(req.Username, req.Password)
+	if err != nil {
+		if err.Error() == "invalid credentials" {
+			models.SendError(w, http.StatusUnauthorized, "Invalid username or password", "")
+			return
+		}
+		fmt.Printf("❌ Login failed: %v\n", err)
+		models.SendError(w, http.StatusInternalServerError, "Failed to log in", err.Error())
+		return
+	}
+
+	auth.SetSessionCookie(w, r, token, session.Expires)
+	models.SendSuccess(w, http.StatusOK, "Logged in successfully", user.ToResponse())
+}
+
+// Logout ends the current session and clears the session cookie
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(auth.SessionCookieName); err == nil && cookie.Value != "" {
+		if err := h.auth.Logout(cookie.Value); err != nil {
+			fmt.Printf("❌ Failed to end session: %v\n", err)
+			models.SendError(w, http.StatusInternalServerError, "Failed to log out", err.Error())
+			return
+		}
+	}
+
+	auth.ClearSessionCookie(w, r)
+	models.SendSuccess(w, http.StatusOK, "Logged out successfully", nil)
+}
+
+// Me returns the signed-in user
+func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		models.SendError(w, http.StatusUnauthorized, "Authentication required", "")
+		return
+	}
+
+	models.SendSuccess(w, http.StatusOK, "User retrieved successfully", user.ToResponse())
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"go-react-backend/auth"
+	"go-react-backend/models"
+)
+
+// currentUser returns the signed-in user, sending a 401 response when the
+// request carries none
+func currentUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		models.SendError(w, http.StatusUnauthorized, "Authentication required", "")
+		return nil, false
+	}
+	return user, true
+}
+
+// sendForbidden sends a 403 response explaining what the user's role lacks
+func sendForbidden(w http.ResponseWriter, reason string) {
+	models.SendError(w, http.StatusForbidden, "Forbidden", reason)
+}
+
+// authorizeBlogCreate returns why user may not create the requested blog, or
+// "" if they may. Users who can't edit other people's blogs have the blog
+// attributed to them when the request names no author.
+func authorizeBlogCreate(user *models.User, req *models.CreateBlogRequest) string {
+	if !user.Can(models.PermissionCreateBlogs) {
+		return "Your role cannot create blogs"
+	}
+	if req.Published && !user.Can(models.PermissionPublishBlogs) {
+		return "Your role cannot publish blogs; save it as a draft instead"
+	}
+	if (req.PublishAt != nil || req.UnpublishAt != nil) && !user.Can(models.PermissionPublishBlogs) {
+		return "Your role cannot schedule blogs"
+	}
+	if !user.Can(models.PermissionEditAnyBlog) {
+		if req.AuthorUsername == "" {
+			req.AuthorUsername = user.Username
+		} else if !strings.EqualFold(req.AuthorUsername, user.Username) {
+			return "Your role can only create blogs under your own username"
+		}
+	}
+	return ""
+}
+
+// authorizeBlogUpdate returns why user may not apply updates to blog, or ""
+// if they may
+func authorizeBlogUpdate(user *models.User, blog *models.Blog, updates models.UpdateBlogRequest) string {
+	if !user.CanEditBlog(blog) {
+		return "Your role can only edit your own blogs"
+	}
+	if updates.Published != nil && *updates.Published != blog.Published && !user.Can(models.PermissionPublishBlogs) {
+		return "Your role cannot publish or unpublish blogs"
+	}
+	if scheduleChanges(blog, updates) && !user.Can(models.PermissionPublishBlogs) {
+		return "Your role cannot schedule blogs"
+	}
+	if updates.AuthorUsername != nil && !user.Can(models.PermissionEditAnyBlog) &&
+		!strings.EqualFold(*updates.AuthorUsername, user.Username) {
+		return "Your role cannot hand blogs to another author"
+	}
+	return ""
+}
+
+// authorizeBlogDelete returns why user may not delete (or restore) blog, or
+// "" if they may
+func authorizeBlogDelete(user *models.User, blog *models.Blog) string {
+	if !user.CanDeleteBlog(blog) {
+		return "Your role can only delete your own blogs"
+	}
+	return ""
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-react-backend/auth"
+	"go-react-backend/models"
+	"go-react-backend/storage"
+	"go-react-backend/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// testUsers holds one user per role. The author owns "author-post"; the
+// other seeded posts belong to someone else or to the viewer.
+var testUsers = map[string]*models.User{
+	models.RoleAdmin:  {Username: "ada", Role: models.RoleAdmin},
+	models.RoleEditor: {Username: "ed", Role: models.RoleEditor},
+	models.RoleAuthor: {Username: "alice", Role: models.RoleAuthor},
+	models.RoleViewer: {Username: "vic", Role: models.RoleViewer},
+}
+
+// newTestBlogHandler returns a handler over a fresh file store seeded with a
+// draft by the author, one by another author and one by the viewer
+func newTestBlogHandler(t *testing.T) *BlogHandler {
+	t.Helper()
+	store, err := storage.NewFileBlogStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileBlogStore: %v", err)
+	}
+	for slug, author := range map[string]string{
+		"author-post": "alice",
+		"other-post":  "bob",
+		"viewer-post": "vic",
+	} {
+		blog := models.Blog{Title: slug, Content: "Hello", Slug: slug, AuthorUsername: author}
+		if _, err := store.CreateBlog(blog); err != nil {
+			t.Fatalf("CreateBlog(%s): %v", slug, err)
+		}
+	}
+	return NewBlogHandler(store, utils.DefaultImageConfig(), nil)
+}
+
+// newFormRequest builds a multipart request sent by user, with slug as the
+// route's slug variable
+func newFormRequest(t *testing.T, method, slug string, fields map[string]string, user *models.User) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			t.Fatalf("WriteField: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	req := httptest.NewRequest(method, "/api/blogs/"+slug, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("If-Match", "*")
+	req = mux.SetURLVars(req, map[string]string{"slug": slug})
+	return req.WithContext(auth.WithUser(req.Context(), user))
+}
+
+func TestCreateBlogAuthorization(t *testing.T) {
+	publishAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name   string
+		role   string
+		fields map[string]string
+		want   int
+	}{
+		{"admin draft", models.RoleAdmin, nil, http.StatusCreated},
+		{"editor draft", models.RoleEditor, nil, http.StatusCreated},
+		{"author draft", models.RoleAuthor, nil, http.StatusCreated},
+		{"viewer draft", models.RoleViewer, nil, http.StatusForbidden},
+
+		{"admin published", models.RoleAdmin, map[string]string{"published": "true"}, http.StatusCreated},
+		{"editor published", models.RoleEditor, map[string]string{"published": "true"}, http.StatusCreated},
+		{"author published", models.RoleAuthor, map[string]string{"published": "true"}, http.StatusForbidden},
+		{"viewer published", models.RoleViewer, map[string]string{"published": "true"}, http.StatusForbidden},
+
+		{"admin scheduled", models.RoleAdmin, map[string]string{"publish_at": publishAt}, http.StatusCreated},
+		{"editor scheduled", models.RoleEditor, map[string]string{"publish_at": publishAt}, http.StatusCreated},
+		{"author scheduled", models.RoleAuthor, map[string]string{"publish_at": publishAt}, http.StatusForbidden},
+		{"viewer scheduled", models.RoleViewer, map[string]string{"publish_at": publishAt}, http.StatusForbidden},
+
+		{"admin for another author", models.RoleAdmin, map[string]string{"author_username": "bob"}, http.StatusCreated},
+		{"editor for another author", models.RoleEditor, map[string]string{"author_username": "bob"}, http.StatusCreated},
+		{"author for another author", models.RoleAuthor, map[string]string{"author_username": "bob"}, http.StatusForbidden},
+		{"author under own name", models.RoleAuthor, map[string]string{"author_username": "alice"}, http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestBlogHandler(t)
+			fields := map[string]string{"title": "New post", "content": "Hello"}
+			for name, value := range tt.fields {
+				fields[name] = value
+			}
+
+			rec := httptest.NewRecorder()
+			h.CreateBlog(rec, newFormRequest(t, http.MethodPost, "", fields, testUsers[tt.role]))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestUpdateBlogAuthorization(t *testing.T) {
+	publishAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	edit := map[string]string{"content": "Edited"}
+	publish := map[string]string{"published": "true"}
+	schedule := map[string]string{"publish_at": publishAt}
+
+	tests := []struct {
+		name   string
+		role   string
+		slug   string
+		fields map[string]string
+		want   int
+	}{
+		{"admin edits other's", models.RoleAdmin, "other-post", edit, http.StatusOK},
+		{"editor edits other's", models.RoleEditor, "other-post", edit, http.StatusOK},
+		{"author edits own", models.RoleAuthor, "author-post", edit, http.StatusOK},
+		{"author edits other's", models.RoleAuthor, "other-post", edit, http.StatusForbidden},
+		{"viewer edits own", models.RoleViewer, "viewer-post", edit, http.StatusForbidden},
+		{"viewer edits other's", models.RoleViewer, "other-post", edit, http.StatusForbidden},
+
+		{"admin publishes other's", models.RoleAdmin, "other-post", publish, http.StatusOK},
+		{"editor publishes other's", models.RoleEditor, "other-post", publish, http.StatusOK},
+		{"author publishes own", models.RoleAuthor, "author-post", publish, http.StatusForbidden},
+		{"author publishes other's", models.RoleAuthor, "other-post", publish, http.StatusForbidden},
+		{"viewer publishes own", models.RoleViewer, "viewer-post", publish, http.StatusForbidden},
+
+		{"admin schedules other's", models.RoleAdmin, "other-post", schedule, http.StatusOK},
+		{"editor schedules other's", models.RoleEditor, "other-post", schedule, http.StatusOK},
+		{"author schedules own", models.RoleAuthor, "author-post", schedule, http.StatusForbidden},
+		{"author schedules other's", models.RoleAuthor, "other-post", schedule, http.StatusForbidden},
+		{"viewer schedules own", models.RoleViewer, "viewer-post", schedule, http.StatusForbidden},
+
+		{"author keeps own draft unpublished", models.RoleAuthor, "author-post", map[string]string{"published": "false"}, http.StatusOK},
+		{"author hands own to another author", models.RoleAuthor, "author-post", map[string]string{"content": "Edited", "author_username": "bob"}, http.StatusForbidden},
+		{"editor hands other's to another author", models.RoleEditor, "other-post", map[string]string{"content": "Edited", "author_username": "alice"}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestBlogHandler(t)
+
+			rec := httptest.NewRecorder()
+			h.UpdateBlogBySlug(rec, newFormRequest(t, http.MethodPut, tt.slug, tt.fields, testUsers[tt.role]))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestDeleteBlogAuthorization(t *testing.T) {
+	tests := []struct {
+		name string
+		role string
+		slug string
+		want int
+	}{
+		{"admin deletes other's", models.RoleAdmin, "other-post", http.StatusOK},
+		{"editor deletes other's", models.RoleEditor, "other-post", http.StatusOK},
+		{"author deletes own", models.RoleAuthor, "author-post", http.StatusOK},
+		{"author deletes other's", models.RoleAuthor, "other-post", http.StatusForbidden},
+		{"viewer deletes own", models.RoleViewer, "viewer-post", http.StatusForbidden},
+		{"viewer deletes other's", models.RoleViewer, "other-post", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestBlogHandler(t)
+
+			rec := httptest.NewRecorder()
+			h.DeleteBlogBySlug(rec, newFormRequest(t, http.MethodDelete, tt.slug, nil, testUsers[tt.role]))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+
+			// A refused delete leaves the blog in place
+			_, err := h.store.GetBlogBySlug(tt.slug)
+			if deleted := err != nil; deleted != (tt.want == http.StatusOK) {
+				t.Fatalf("blog deleted = %v after status %d", deleted, rec.Code)
+			}
+		})
+	}
+}
+
+// handOverStore gives a blog to another author straight after it is first
+// read, as an update landing between a handler's check and its write would
+type handOverStore struct {
+	models.BlogStore
+	author string
+	reads  int
+}
+
+func (s *handOverStore) GetBlogBySlug(slug string) (*models.Blog, error) {
+	blog, err := s.BlogStore.GetBlogBySlug(slug)
+	s.reads++
+	if err == nil && s.reads == 1 {
+		if _, err := s.BlogStore.UpdateBlogBySlug(slug, models.UpdateBlogRequest{AuthorUsername: &s.author}); err != nil {
+			return nil, err
+		}
+	}
+	return blog, err
+}
+
+func TestUpdateBlogAuthorizationUnderLock(t *testing.T) {
+	h := newTestBlogHandler(t)
+	h.store = &handOverStore{BlogStore: h.store, author: "bob"}
+
+	rec := httptest.NewRecorder()
+	h.UpdateBlogBySlug(rec, newFormRequest(t, http.MethodPut, "author-post", map[string]string{"content": "Edited"}, testUsers[models.RoleAuthor]))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusForbidden, rec.Body)
+	}
+
+	blog, err := h.store.GetBlogBySlug("author-post")
+	if err != nil {
+		t.Fatalf("GetBlogBySlug: %v", err)
+	}
+	if blog.Content != "Hello" {
+		t.Errorf("content = %q after a refused update, want it unchanged", blog.Content)
+	}
+}
@@ -1,37 +1,152 @@
 package handlers
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"net/http"
-	"os"
+	"net/url"
 	"path/filepath"
+	"strconv"
+	"sync"
 	"time"
 
+	"go-react-backend/auth"
 	"go-react-backend/models"
-	"go-react-backend/storage"
 	"go-react-backend/utils"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 )
 
 // BlogHandler handles blog-related HTTP requests
 type BlogHandler struct {
-	store models.BlogStore
+	store         models.BlogStore
+	imageConfig   utils.ImageConfig
+	previewSigner *auth.PreviewSigner // Checks preview tokens on image requests
+
+	// writeMu makes checking a blog's version and writing it one step, so
+	// two conditional writes can't both pass against the same version
+	writeMu sync.Mutex
+}
+
+// NewBlogHandler creates a new blog handler that processes uploaded images
+// with imageConfig and serves unpublished blogs' images to holders of preview
+// tokens from previewSigner
+func NewBlogHandler(store models.BlogStore, imageConfig utils.ImageConfig, previewSigner *auth.PreviewSigner) *BlogHandler {
+	return &BlogHandler{store: store, imageConfig: imageConfig, previewSigner: previewSigner}
+}
+
+// WriteLock returns the lock under which the handler checks a blog's version
+// and writes it, for other writers (such as the publish scheduler) to hold
+// while they write
+func (h *BlogHandler) WriteLock() sync.Locker {
+	return &h.writeMu
+}
+
+// GetBlogs lists the blogs the caller may see (see canViewBlog), filtered,
+// sorted and paginated by query parameters
+func (h *BlogHandler) GetBlogs(w http.ResponseWriter, r *http.Request) {
+	query, err := parseBlogQuery(r)
+	if err != nil {
+		models.SendError(w, http.StatusBadRequest, "Invalid query parameters", err.Error())
+		return
+	}
+
+	blogs, err := h.store.GetAllBlogs()
+	if err != nil {
+		fmt.Printf("❌ Failed to list blogs: %v\n", err)
+		models.SendError(w, http.StatusInternalServerError, "Failed to list blogs", err.Error())
+		return
+	}
+
+	page, total := query.apply(visibleBlogs(r, blogs))
+
+	responses := make([]models.BlogResponse, 0, len(page))
+	for _, blog := range page {
+		responses = append(responses, blog.ToResponse())
+	}
+
+	models.SendSuccess(w, http.StatusOK, "Blogs retrieved successfully", models.BlogListResponse{
+		Blogs:      responses,
+		Page:       query.page,
+		PerPage:    query.perPage,
+		Total:      total,
+		TotalPages: (total + query.perPage - 1) / query.perPage,
+	})
 }
 
-// NewBlogHandler creates a new blog handler
-func NewBlogHandler(store models.BlogStore) *BlogHandler {
-	return &BlogHandler{store: store}
+// GetBlogBySlug returns a single blog by slug. Former slugs redirect to the
+// blog's current URL. Blogs the caller may not see are reported as not found.
+func (h *BlogHandler) GetBlogBySlug(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+	now := time.Now()
+
+	blog, err := h.store.GetBlogBySlug(slug)
+	if err == nil && !canViewBlog(r, blog, now) {
+		blog, err = nil, errors.New("blog not found")
+	}
+	if err != nil {
+		if err.Error() != "blog not found" {
+			models.SendError(w, http.StatusInternalServerError, "Failed to get blog", err.Error())
+			return
+		}
+
+		if history, ok := h.store.(models.SlugHistoryStore); ok {
+			if renamedBlog, resolveErr := history.ResolveSlug(slug); resolveErr == nil && renamedBlog.Slug != slug && canViewBlog(r, renamedBlog, now) {
+				http.Redirect(w, r, "/api/blogs/"+url.PathEscape(renamedBlog.Slug), http.StatusMovedPermanently)
+				return
+			}
+		}
+
+		models.SendError(w, http.StatusNotFound, "Blog not found", err.Error())
+		return
+	}
+
+	if notModified(w, r, blog) {
+		return
+	}
+	setETag(w, blog)
+	models.SendSuccess(w, http.StatusOK, "Blog retrieved successfully", blog.ToResponse())
 }
 
+// GetBlogByID returns a single blog by its ID, if the caller may see it
+func (h *BlogHandler) GetBlogByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		models.SendError(w, http.StatusBadRequest, "Invalid blog ID", err.Error())
+		return
+	}
 
+	blog, err := h.store.GetBlogByID(id)
+	if err == nil && !canViewBlog(r, blog, time.Now()) {
+		blog, err = nil, errors.New("blog not found")
+	}
+	if err != nil {
+		if err.Error() == "blog not found" {
+			models.SendError(w, http.StatusNotFound, "Blog not found", err.Error())
+		} else {
+			models.SendError(w, http.StatusInternalServerError, "Failed to get blog", err.Error())
+		}
+		return
+	}
 
+	if notModified(w, r, blog) {
+		return
+	}
+	setETag(w, blog)
+	models.SendSuccess(w, http.StatusOK, "Blog retrieved successfully", blog.ToResponse())
+}
 
 // CreateBlog creates a new blog
 func (h *BlogHandler) CreateBlog(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
 	var req models.CreateBlogRequest
-	var imageData []byte
-	var imageFilename string
+	var image *utils.ProcessedImage
 
 	// Always expect multipart form data
 	if err := r.ParseMultipartForm(10 << 20); err != nil { // 10MB
@@ -48,6 +163,22 @@ func (h *BlogHandler) CreateBlog(w http.ResponseWriter, r *http.Request) {
 	req.MetaDescription = r.FormValue("meta_description")
 	req.Slug = r.FormValue("slug")
 	req.Published = r.FormValue("published") == "true"
+	req.Tags, _ = formTags(r)
+	req.Category, _ = formCategory(r)
+	var scheduleErr error
+	if req.PublishAt, scheduleErr = formScheduleCreate(r, "publish_at"); scheduleErr == nil {
+		req.UnpublishAt, scheduleErr = formScheduleCreate(r, "unpublish_at")
+	}
+	if scheduleErr != nil {
+		models.SendError(w, http.StatusBadRequest, "Validation failed", scheduleErr.Error())
+		return
+	}
+
+	// Check the user's role before doing any image work
+	if reason := authorizeBlogCreate(user, &req); reason != "" {
+		sendForbidden(w, reason)
+		return
+	}
 
 	// Handle image upload if present
 	if file, header, err := r.FormFile("image"); err == nil {
@@ -61,9 +192,8 @@ func (h *BlogHandler) CreateBlog(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Process the image
-		config := utils.DefaultImageConfig()
 		var err error
-		imageData, imageFilename, err = utils.ProcessImage(file, header, config)
+		image, err = utils.ProcessImage(file, header, h.imageConfig)
 		if err != nil {
 			fmt.Printf("❌ Image processing failed: %v\n", err)
 			models.SendError(w, http.StatusInternalServerError, "Failed to process image", err.Error())
@@ -85,42 +215,42 @@ func (h *BlogHandler) CreateBlog(w http.ResponseWriter, r *http.Request) {
 	newBlog := models.Blog{
 		Title:           req.Title,
 		Content:         req.Content,
-		Image:           imageFilename,
 		AuthorName:      req.AuthorName,
 		AuthorUsername:  req.AuthorUsername,
 		MetaName:        req.MetaName,
 		MetaDescription: req.MetaDescription,
+		Tags:            req.Tags,
+		Category:        req.Category,
 		Slug:            req.Slug,
 		Published:       req.Published,
+		PublishAt:       req.PublishAt,
+		UnpublishAt:     req.UnpublishAt,
+	}
+	if image != nil {
+		hero, err := h.storeImage(image, nil, "", "")
+		if err != nil {
+			fmt.Printf("❌ Failed to save image: %v\n", err)
+			models.SendError(w, http.StatusInternalServerError, "Failed to save image", err.Error())
+			return
+		}
+		newBlog.Media = []models.MediaItem{hero}
+		newBlog.SetHeroImage(&hero)
 	}
 
 	createdBlog, err := h.store.CreateBlog(newBlog)
 	if err != nil {
-		models.SendError(w, http.StatusInternalServerError, "Failed to create blog", err.Error())
-		return
-	}
-
-	// Save image if provided - directly in the blog directory
-	if imageData != nil && imageFilename != "" {
-		blogDir := h.store.(*storage.FileBlogStore).GetBlogDir(createdBlog.Slug)
-		imagePath := filepath.Join(blogDir, imageFilename)
-		
-		// Create blog directory if it doesn't exist
-		if err := os.MkdirAll(blogDir, 0755); err != nil {
-			fmt.Printf("❌ Failed to create blog directory %s: %v\n", blogDir, err)
+		if errors.Is(err, models.ErrSlugExists) {
+			models.SendError(w, http.StatusConflict, "Slug already exists", err.Error())
 		} else {
-			// Save image file
-			if err := os.WriteFile(imagePath, imageData, 0644); err != nil {
-				fmt.Printf("❌ Failed to write image to %s: %v\n", imagePath, err)
-			} 
+			models.SendError(w, http.StatusInternalServerError, "Failed to create blog", err.Error())
 		}
+		return
 	}
 
+	setETag(w, &createdBlog)
 	models.SendSuccess(w, http.StatusCreated, "Blog created successfully", createdBlog.ToResponse())
 }
 
-
-
 // UpdateBlogBySlug updates an existing blog by slug
 func (h *BlogHandler) UpdateBlogBySlug(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -131,9 +261,13 @@ func (h *BlogHandler) UpdateBlogBySlug(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
 	var req models.UpdateBlogRequest
-	var imageData []byte
-	var imageFilename string
+	var image *utils.ProcessedImage
 
 	// Always expect multipart form data
 	if err := r.ParseMultipartForm(10 << 20); err != nil { // 10MB
@@ -167,6 +301,35 @@ func (h *BlogHandler) UpdateBlogBySlug(w http.ResponseWriter, r *http.Request) {
 		publishedBool := published == "true"
 		req.Published = &publishedBool
 	}
+	if tags, ok := formTags(r); ok {
+		req.Tags = &tags
+	}
+	if category, ok := formCategory(r); ok {
+		req.Category = &category
+	}
+	var scheduleErr error
+	if req.PublishAt, scheduleErr = formScheduleUpdate(r, "publish_at"); scheduleErr == nil {
+		req.UnpublishAt, scheduleErr = formScheduleUpdate(r, "unpublish_at")
+	}
+	if scheduleErr != nil {
+		models.SendError(w, http.StatusBadRequest, "Validation failed", scheduleErr.Error())
+		return
+	}
+
+	// Check the user's role against the blog before doing any image work
+	existingBlog, err := h.store.GetBlogBySlug(slug)
+	if err != nil {
+		if err.Error() == "blog not found" {
+			models.SendError(w, http.StatusNotFound, "Blog not found", err.Error())
+		} else {
+			models.SendError(w, http.StatusInternalServerError, "Failed to update blog", err.Error())
+		}
+		return
+	}
+	if reason := authorizeBlogUpdate(user, existingBlog, req); reason != "" {
+		sendForbidden(w, reason)
+		return
+	}
 
 	// Handle image upload if present
 	if file, header, err := r.FormFile("image"); err == nil {
@@ -180,48 +343,67 @@ func (h *BlogHandler) UpdateBlogBySlug(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Process the image
-		config := utils.DefaultImageConfig()
 		var err error
-		imageData, imageFilename, err = utils.ProcessImage(file, header, config)
+		image, err = utils.ProcessImage(file, header, h.imageConfig)
 		if err != nil {
 			fmt.Printf("❌ Image processing failed: %v\n", err)
 			models.SendError(w, http.StatusInternalServerError, "Failed to process image", err.Error())
 			return
 		}
-		
-		// Save the image directly to the blog's directory
-		blogDir := h.store.(*storage.FileBlogStore).GetBlogDir(slug)
-		imagePath := filepath.Join(blogDir, imageFilename)
-		
-		// Create blog directory if it doesn't exist
-		if err := os.MkdirAll(blogDir, 0755); err != nil {
-			fmt.Printf("❌ Failed to create blog directory %s: %v\n", blogDir, err)
-			models.SendError(w, http.StatusInternalServerError, "Failed to save image", err.Error())
-			return
-		}
-		
-		// Save image file
-		if err := os.WriteFile(imagePath, imageData, 0644); err != nil {
-			fmt.Printf("❌ Failed to write image to %s: %v\n", imagePath, err)
-			models.SendError(w, http.StatusInternalServerError, "Failed to save image", err.Error())
-			return
-		}
-		
-		req.Image = &imageFilename
 	}
 
 	// Validate that at least one field is being updated
-	if req.Title == nil && req.Content == nil && req.Image == nil && req.MetaName == nil && req.MetaDescription == nil && req.Slug == nil && req.Published == nil {
+	if req.Title == nil && req.Content == nil && image == nil && req.MetaName == nil && req.MetaDescription == nil && req.Tags == nil && req.Category == nil && req.Slug == nil && req.Published == nil && req.PublishAt == nil && req.UnpublishAt == nil {
 		models.SendError(w, http.StatusBadRequest, "No fields to update", "At least one field must be provided")
 		return
 	}
 
-	// Update blog by slug
-	updatedBlog, err := h.store.UpdateBlogBySlug(slug, req)
+	if err := req.Validate(); err != nil {
+		models.SendError(w, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+	if err := validateScheduleUpdate(existingBlog, req); err != nil {
+		models.SendError(w, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	// Update blog by slug, unless someone else saved it since the client
+	// loaded it
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	currentBlog, err := h.store.GetBlogBySlug(slug)
+	if err == nil {
+		// The blog may have changed hands or been published since the
+		// check above, which ran without the lock
+		if reason := authorizeBlogUpdate(user, currentBlog, req); reason != "" {
+			sendForbidden(w, reason)
+			return
+		}
+	}
+	if err == nil && !checkIfMatch(w, r, currentBlog) {
+		return
+	}
+	if err == nil && image != nil {
+		// The upload replaces the hero image in the media collection
+		media := withoutMedia(currentBlog.MediaItems(), currentBlog.Image)
+		hero, storeErr := h.storeImage(image, media, "", "")
+		if storeErr != nil {
+			fmt.Printf("❌ Failed to save image: %v\n", storeErr)
+			models.SendError(w, http.StatusInternalServerError, "Failed to save image", storeErr.Error())
+			return
+		}
+		media = append(media, hero)
+		req.Media = &media
+		req.SetHeroImage(&hero)
+	}
+	var updatedBlog *models.Blog
+	if err == nil {
+		updatedBlog, err = h.store.UpdateBlogBySlug(slug, req)
+	}
 	if err != nil {
 		if err.Error() == "blog not found" {
 			models.SendError(w, http.StatusNotFound, "Blog not found", err.Error())
-		} else if err.Error() == "slug already exists" {
+		} else if errors.Is(err, models.ErrSlugExists) {
 			models.SendError(w, http.StatusConflict, "Slug already exists", err.Error())
 		} else {
 			models.SendError(w, http.StatusInternalServerError, "Failed to update blog", err.Error())
@@ -229,18 +411,12 @@ func (h *BlogHandler) UpdateBlogBySlug(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Save image if provided
-	if imageData != nil && imageFilename != "" {
-		if err := h.store.SaveBlogImage(updatedBlog.Slug, imageFilename, imageData); err != nil {
-			// Log error but don't fail the request - blog was updated successfully
-			fmt.Printf("Warning: Failed to save image for blog %s: %v\n", updatedBlog.Slug, err)
-		}
-	}
-
+	setETag(w, updatedBlog)
 	models.SendSuccess(w, http.StatusOK, "Blog updated successfully", updatedBlog.ToResponse())
 }
 
-// DeleteBlogBySlug deletes a blog by slug
+// DeleteBlogBySlug deletes a blog by slug if the request's If-Match header
+// names its current version
 func (h *BlogHandler) DeleteBlogBySlug(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	slug := vars["slug"]
@@ -250,7 +426,24 @@ func (h *BlogHandler) DeleteBlogBySlug(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.store.DeleteBlogBySlug(slug)
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	existingBlog, err := h.store.GetBlogBySlug(slug)
+	if err == nil {
+		if reason := authorizeBlogDelete(user, existingBlog); reason != "" {
+			sendForbidden(w, reason)
+			return
+		}
+		if !checkIfMatch(w, r, existingBlog) {
+			return
+		}
+		err = h.store.DeleteBlogBySlug(slug)
+	}
 	if err != nil {
 		if err.Error() == "blog not found" {
 			models.SendError(w, http.StatusNotFound, "Blog not found", err.Error())
@@ -260,15 +453,19 @@ func (h *BlogHandler) DeleteBlogBySlug(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	models.SendSuccess(w, http.StatusOK, "Blog deleted successfully", nil)
+	models.SendSuccess(w, http.StatusOK, "Blog moved to trash", nil)
 }
 
-
-// ServeImage serves image files for blogs
+// ServeImage serves the image files of blogs' media collections. With a w
+// query parameter, a request for a media item's image is answered with the
+// variant best suited to that width. Images of blogs the caller may not see
+// are reported as not found, unless the request has a preview token for the
+// blog.
 func (h *BlogHandler) ServeImage(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	slug := vars["slug"]
 	filename := vars["filename"]
+	now := time.Now()
 
 	if slug == "" || filename == "" {
 		fmt.Printf("❌ Missing slug or filename\n")
@@ -284,26 +481,58 @@ func (h *BlogHandler) ServeImage(w http.ResponseWriter, r *http.Request) {
 
 	// Get the blog to verify it exists and get the image
 	blog, err := h.store.GetBlogBySlug(slug)
+	if err == nil && !canPreviewBlog(r, blog, h.previewSigner, now) {
+		blog, err = nil, errors.New("blog not found")
+	}
 	if err != nil {
+		// Permanently redirect links that use one of the blog's former slugs,
+		// without revealing where a blog the caller may not see moved to
+		if history, ok := h.store.(models.SlugHistoryStore); ok {
+			if renamedBlog, resolveErr := history.ResolveSlug(slug); resolveErr == nil && renamedBlog.Slug != slug && canPreviewBlog(r, renamedBlog, h.previewSigner, now) {
+				target := models.ImageURL(renamedBlog.Slug, filename)
+				if r.URL.RawQuery != "" {
+					target += "?" + r.URL.RawQuery
+				}
+				http.Redirect(w, r, target, http.StatusMovedPermanently)
+				return
+			}
+		}
+
 		fmt.Printf("❌ Blog not found: %v\n", err)
 		models.SendError(w, http.StatusNotFound, "Blog not found", "")
 		return
 	}
 
-	// Check if the requested image matches the blog's image
-	if blog.Image != filename {
-		fmt.Printf("❌ Image mismatch: blog has '%s', requested '%s'\n", blog.Image, filename)
+	// Check if the requested image is one of the blog's image files
+	if !blog.HasImageFile(filename) {
+		fmt.Printf("❌ Image not in media of blog %s: '%s'\n", slug, filename)
 		models.SendError(w, http.StatusNotFound, "Image not found", "")
 		return
 	}
 
-	// Read the image file from storage
-	blogDir := h.store.(*storage.FileBlogStore).GetBlogDir(slug)
-	imagePath := filepath.Join(blogDir, filename)
-	
-	// Check if file exists
-	if _, err := os.Stat(imagePath); os.IsNotExist(err) {
-		fmt.Printf("❌ Image file not found: %s\n", imagePath)
+	// Pick the variant for the requested width
+	if widthParam := r.URL.Query().Get("w"); widthParam != "" {
+		width, err := strconv.Atoi(widthParam)
+		if err != nil || width <= 0 {
+			models.SendError(w, http.StatusBadRequest, "Invalid width", "w must be a positive number of pixels")
+			return
+		}
+		if item := blog.MediaForFile(filename); item != nil && filename == item.Filename {
+			filename = item.FileForWidth(width)
+		}
+	}
+
+	// Read the image from the media library, or from the blog's own files
+	// for images uploaded before the library existed
+	var imageData []byte
+	item := blog.MediaForFile(filename)
+	if library, ok := h.store.(models.MediaLibrary); ok && item != nil && item.Hash != "" {
+		imageData, err = library.GetAssetFile(item.Hash, item.AssetFile(filename))
+	} else {
+		imageData, err = h.store.GetBlogImage(slug, filename)
+	}
+	if err != nil {
+		fmt.Printf("❌ Image file not found for blog %s: %v\n", slug, err)
 		// Prevent caching of 404 responses
 		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
 		w.Header().Set("Pragma", "no-cache")
@@ -313,12 +542,28 @@ func (h *BlogHandler) ServeImage(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	// Set appropriate headers
-	w.Header().Set("Content-Type", utils.GetImageMimeType())
-	w.Header().Set("Cache-Control", "public, max-age=31536000") // 1 year cache
-	w.Header().Set("ETag", fmt.Sprintf("\"%s-%s\"", slug, filename)) // ETag for cache validation
-	w.Header().Set("Last-Modified", time.Now().Format(http.TimeFormat)) // Last modified
+	// Images stored before their type was recorded are named after it
+	contentType := ""
+	etag := fmt.Sprintf("\"%s-%s\"", slug, filename)
+	if item != nil {
+		contentType = item.MimeType
+		if item.Hash != "" {
+			// Library files never change, whatever the post calls them
+			etag = fmt.Sprintf("\"%s-%s\"", item.Hash, item.AssetFile(filename))
+		}
+	}
+	if contentType == "" {
+		contentType = utils.MimeTypeForFilename(filename)
+	}
+	w.Header().Set("Content-Type", contentType)
+	if blog.IsLive(now) {
+		w.Header().Set("Cache-Control", "public, max-age=31536000") // 1 year cache
+	} else {
+		// Images of unpublished blogs must stay out of shared caches
+		w.Header().Set("Cache-Control", "private, no-store")
+	}
+	w.Header().Set("ETag", etag) // ETag for cache validation
 	
-	// Serve the file
-	http.ServeFile(w, r, imagePath)
-}
-
+	// Serve the image, using the blog's update time as Last-Modified
+	http.ServeContent(w, r, filename, blog.Updated, bytes.NewReader(imageData))
+}
\ No newline at end of file
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-react-backend/models"
+)
+
+func TestCreateBlogSlugConflict(t *testing.T) {
+	h := newTestBlogHandler(t)
+	fields := map[string]string{"title": "Again", "content": "Hello", "slug": "other-post"}
+
+	rec := httptest.NewRecorder()
+	h.CreateBlog(rec, newFormRequest(t, http.MethodPost, "", fields, testUsers[models.RoleAdmin]))
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusConflict, rec.Body)
+	}
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-react-backend/models"
+)
+
+// Pagination defaults and limits for blog listings. Capping the page keeps
+// the offset it works out to from overflowing.
+const (
+	defaultBlogsPerPage = 10
+	maxBlogsPerPage     = 100
+	maxBlogPage         = 10000
+)
+
+// blogQuery is a filtered, sorted and paginated blog listing request
+type blogQuery struct {
+	status   string    // "all", "published", "scheduled" or "draft"
+	author   string    // author username, matched case-insensitively
+	tags     []string  // tags a blog must all have
+	category string    // category, matched case-insensitively
+	from     time.Time // earliest creation time, zero for no lower bound
+	to       time.Time // latest creation time, zero for no upper bound
+	sort     string    // "created", "updated" or "title"
+	desc     bool
+	page     int
+	perPage  int
+}
+
+// parseBlogQuery reads a blogQuery from the request's query string:
+// page, per_page, status, author, tag (repeated or comma-separated),
+// category, sort (prefix with "-" for descending), from and to (RFC 3339
+// timestamps or YYYY-MM-DD dates)
+func parseBlogQuery(r *http.Request) (blogQuery, error) {
+	params := r.URL.Query()
+	query := blogQuery{
+		status:   "all",
+		author:   params.Get("author"),
+		category: models.NormalizeCategory(params.Get("category")),
+		sort:     "created",
+		desc:     true,
+		page:     1,
+		perPage:  defaultBlogsPerPage,
+	}
+
+	if page := params.Get("page"); page != "" {
+		parsed, err := strconv.Atoi(page)
+		if err != nil || parsed < 1 || parsed > maxBlogPage {
+			return blogQuery{}, fmt.Errorf("page must be between 1 and %d", maxBlogPage)
+		}
+		query.page = parsed
+	}
+
+	if perPage := params.Get("per_page"); perPage != "" {
+		parsed, err := strconv.Atoi(perPage)
+		if err != nil || parsed < 1 || parsed > maxBlogsPerPage {
+			return blogQuery{}, fmt.Errorf("per_page must be between 1 and %d", maxBlogsPerPage)
+		}
+		query.perPage = parsed
+	}
+
+	if status := params.Get("status"); status != "" {
+		switch status {
+		case "all", "published", "scheduled", "draft":
+			query.status = status
+		default:
+			return blogQuery{}, fmt.Errorf("status must be one of all, published, scheduled or draft")
+		}
+	}
+
+	for _, tag := range params["tag"] {
+		query.tags = append(query.tags, strings.Split(tag, ",")...)
+	}
+	query.tags = models.NormalizeTags(query.tags)
+
+	if sortParam := params.Get("sort"); sortParam != "" {
+		query.desc = strings.HasPrefix(sortParam, "-")
+		query.sort = strings.TrimPrefix(sortParam, "-")
+		switch query.sort {
+		case "created", "updated", "title":
+		default:
+			return blogQuery{}, fmt.Errorf("sort must be created, updated or title, optionally prefixed with -")
+		}
+	}
+
+	var err error
+	if query.from, err = parseQueryTime(params.Get("from"), false); err != nil {
+		return blogQuery{}, fmt.Errorf("from: %w", err)
+	}
+	if query.to, err = parseQueryTime(params.Get("to"), true); err != nil {
+		return blogQuery{}, fmt.Errorf("to: %w", err)
+	}
+	if !query.from.IsZero() && !query.to.IsZero() && query.to.Before(query.from) {
+		return blogQuery{}, fmt.Errorf("to must not be before from")
+	}
+
+	return query, nil
+}
+
+// parseQueryTime parses an RFC 3339 timestamp or a YYYY-MM-DD date. A date
+// used as an upper bound covers the whole day.
+func parseQueryTime(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected an RFC 3339 timestamp or YYYY-MM-DD date, got %q", value)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
+
+// matches reports whether a blog passes the query's filters at now.
+// Published blogs are the live ones, scheduled blogs are waiting for their
+// publish_at time, and drafts are everything else.
+func (q blogQuery) matches(blog models.Blog, now time.Time) bool {
+	switch q.status {
+	case "published":
+		if !blog.IsLive(now) {
+			return false
+		}
+	case "scheduled":
+		if !blog.IsScheduled(now) {
+			return false
+		}
+	case "draft":
+		if blog.IsLive(now) || blog.IsScheduled(now) {
+			return false
+		}
+	}
+	if q.author != "" && !strings.EqualFold(blog.AuthorUsername, q.author) {
+		return false
+	}
+	for _, tag := range q.tags {
+		if !blog.HasTag(tag) {
+			return false
+		}
+	}
+	if q.category != "" && !blog.InCategory(q.category) {
+		return false
+	}
+	if !q.from.IsZero() && blog.Created.Before(q.from) {
+		return false
+	}
+	if !q.to.IsZero() && blog.Created.After(q.to) {
+		return false
+	}
+	return true
+}
+
+// less orders two blogs by the query's sort field, ascending
+func (q blogQuery) less(a, b models.Blog) bool {
+	switch q.sort {
+	case "updated":
+		return a.Updated.Before(b.Updated)
+	case "title":
+		return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+	default:
+		return a.Created.Before(b.Created)
+	}
+}
+
+// apply filters and sorts blogs, returning the requested page and the total
+// number of matching blogs
+func (q blogQuery) apply(blogs []models.Blog) ([]models.Blog, int) {
+	now := time.Now()
+	matching := make([]models.Blog, 0, len(blogs))
+	for _, blog := range blogs {
+		if q.matches(blog, now) {
+			matching = append(matching, blog)
+		}
+	}
+
+	sort.SliceStable(matching, func(i, j int) bool {
+		if q.desc {
+			return q.less(matching[j], matching[i])
+		}
+		return q.less(matching[i], matching[j])
+	})
+
+	start := (q.page - 1) * q.perPage
+	if start > len(matching) {
+		start = len(matching)
+	}
+	end := start + q.perPage
+	if end > len(matching) {
+		end = len(matching)
+	}
+
+	return matching[start:end], len(matching)
+}
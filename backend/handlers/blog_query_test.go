@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetBlogsPageBounds(t *testing.T) {
+	tests := []struct {
+		name string
+		page string
+		want int
+	}{
+		{"first page", "1", http.StatusOK},
+		{"past the last blog", "10000", http.StatusOK},
+		{"zero", "0", http.StatusBadRequest},
+		{"over the cap", "10001", http.StatusBadRequest},
+		{"offset would overflow", "9223372036854775807", http.StatusBadRequest},
+		{"not a number", "two", http.StatusBadRequest},
+	}
+
+	h := newTestBlogHandler(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.GetBlogs(rec, httptest.NewRequest(http.MethodGet, "/api/blogs?per_page=100&page="+tt.page, nil))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
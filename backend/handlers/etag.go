@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"go-react-backend/models"
+)
+
+// setETag sends blog's entity tag with the response
+func setETag(w http.ResponseWriter, blog *models.Blog) {
+	w.Header().Set("ETag", blog.ETag())
+}
+
+// etagListMatches reports whether an If-Match or If-None-Match header value
+// ("*" or a comma-separated list of entity tags) matches etag. Weak tags
+// never match, as If-Match requires strong comparison.
+func etagListMatches(header string, etag string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimSpace(candidate) == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// notModified answers a conditional GET with 304 when the client's
+// If-None-Match header already names blog's current version
+func notModified(w http.ResponseWriter, r *http.Request, blog *models.Blog) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" || !etagListMatches(header, blog.ETag()) {
+		return false
+	}
+	setETag(w, blog)
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// checkIfMatch makes a write conditional on the request's If-Match header
+// naming blog's current version. It sends 428 when the header is missing and
+// 412 with the current blog when it is stale, and reports whether the write
+// may go ahead. "If-Match: *" skips the check.
+func checkIfMatch(w http.ResponseWriter, r *http.Request, blog *models.Blog) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		models.SendError(w, http.StatusPreconditionRequired, "Precondition required",
+			"Send the blog's ETag in an If-Match header so concurrent edits aren't overwritten")
+		return false
+	}
+	if etagListMatches(header, blog.ETag()) {
+		return true
+	}
+
+	// Hand back the current version so the client can merge and retry
+	setETag(w, blog)
+	models.SendJSON(w, http.StatusPreconditionFailed, models.Response{
+		Message:   "Precondition failed",
+		Timestamp: time.Now(),
+		Data:      blog.ToResponse(),
+		Error:     "The blog was changed since it was loaded; the current version is attached",
+	})
+	return false
+}
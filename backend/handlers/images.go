@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go-react-backend/models"
+	"go-react-backend/utils"
+)
+
+// imageVariants lists a processed image's width variants as they are
+// recorded on its blog
+func imageVariants(image *utils.ProcessedImage) []models.ImageVariant {
+	variants := make([]models.ImageVariant, 0, len(image.Variants))
+	for _, variant := range image.Variants {
+		variants = append(variants, models.ImageVariant{
+			Filename: variant.Filename,
+			Width:    variant.Width,
+			Height:   variant.Height,
+		})
+	}
+	return variants
+}
+
+// mediaItem describes a processed image as a member of a blog's media
+// collection
+func mediaItem(image *utils.ProcessedImage, altText string, caption string) models.MediaItem {
+	return models.MediaItem{
+		Filename:  image.Image.Filename,
+		MimeType:  image.Image.MimeType,
+		Width:     image.Image.Width,
+		Height:    image.Image.Height,
+		Variants:  imageVariants(image),
+		Thumbnail: image.Thumbnail.Filename,
+		AltText:   altText,
+		Caption:   caption,
+		Created:   time.Now(),
+
+		Placeholder: image.Placeholder,
+		Color:       image.Color,
+	}
+}
+
+// storeImage adds a processed image to the media library and returns it as
+// a media item for a blog with items. The item is named after the upload,
+// with a suffix if needed so none of its files clash with those of items:
+// photo.jpg, then photo_2.jpg. Content already in the library is reused.
+func (h *BlogHandler) storeImage(image *utils.ProcessedImage, items []models.MediaItem, altText string, caption string) (models.MediaItem, error) {
+	library, ok := h.store.(models.MediaLibrary)
+	if !ok {
+		return models.MediaItem{}, errors.New("media library not supported")
+	}
+
+	uploaded := mediaItem(image, altText, caption)
+	files := make(map[string][]byte)
+	for _, file := range image.Files() {
+		files[uploaded.AssetFile(file.Filename)] = file.Data
+	}
+	asset := models.NewMediaAsset(image.Hash, uploaded)
+	if image.Original != nil {
+		asset.Original = uploaded.AssetFile(image.Original.Filename)
+	}
+	saved, err := library.SaveAsset(asset, files)
+	if err != nil {
+		return models.MediaItem{}, err
+	}
+
+	taken := make(map[string]bool)
+	for _, item := range items {
+		for _, file := range item.Files() {
+			taken[file] = true
+		}
+	}
+	clashes := func(item models.MediaItem) bool {
+		for _, file := range item.Files() {
+			if taken[file] {
+				return true
+			}
+		}
+		return false
+	}
+
+	name := strings.TrimSuffix(uploaded.Filename, filepath.Ext(uploaded.Filename))
+	item := saved.MediaItem(name)
+	for n := 2; clashes(item); n++ {
+		item = saved.MediaItem(fmt.Sprintf("%s_%d", name, n))
+	}
+	item.AltText = altText
+	item.Caption = caption
+	item.Created = uploaded.Created
+	return item, nil
+}
@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go-react-backend/models"
+	"go-react-backend/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// sendMediaError maps media errors to HTTP responses
+func sendMediaError(w http.ResponseWriter, message string, err error) {
+	switch err.Error() {
+	case "blog not found":
+		models.SendError(w, http.StatusNotFound, "Blog not found", err.Error())
+	case "media not found":
+		models.SendError(w, http.StatusNotFound, "Media not found", err.Error())
+	default:
+		models.SendError(w, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+// withoutMedia returns items without the one stored as filename
+func withoutMedia(items []models.MediaItem, filename string) []models.MediaItem {
+	kept := make([]models.MediaItem, 0, len(items))
+	for _, item := range items {
+		if item.Filename != filename {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// findMediaItem returns the index of the item stored as filename in items,
+// or -1
+func findMediaItem(items []models.MediaItem, filename string) int {
+	for i, item := range items {
+		if item.Filename == filename {
+			return i
+		}
+	}
+	return -1
+}
+
+// ListMedia lists a blog's media collection, in upload order
+func (h *BlogHandler) ListMedia(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+
+	blog, err := h.store.GetBlogBySlug(slug)
+	if err == nil && !canViewBlog(r, blog, time.Now()) {
+		err = errors.New("blog not found")
+	}
+	if err != nil {
+		sendMediaError(w, "Failed to list media", err)
+		return
+	}
+
+	models.SendSuccess(w, http.StatusOK, "Media retrieved successfully", blog.MediaResponses())
+}
+
+// UploadMedia adds an image to a blog's media collection. The multipart form
+// carries the image with optional alt_text and caption fields; hero=true
+// also makes it the blog's hero image.
+func (h *BlogHandler) UploadMedia(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	// Parse multipart form (10MB max)
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		models.SendError(w, http.StatusBadRequest, "Failed to parse form", err.Error())
+		return
+	}
+
+	altText := r.FormValue("alt_text")
+	caption := r.FormValue("caption")
+	hero := r.FormValue("hero") == "true"
+	if err := models.ValidateMediaText(altText, caption); err != nil {
+		models.SendError(w, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		models.SendError(w, http.StatusBadRequest, "Image is required", err.Error())
+		return
+	}
+	defer file.Close()
+
+	if err := utils.ValidateImageFile(header); err != nil {
+		models.SendError(w, http.StatusBadRequest, "Invalid image file", err.Error())
+		return
+	}
+
+	// Check the user's role before doing any image work
+	blog, err := h.store.GetBlogBySlug(slug)
+	if err != nil {
+		sendMediaError(w, "Failed to upload media", err)
+		return
+	}
+	if reason := authorizeBlogUpdate(user, blog, models.UpdateBlogRequest{}); reason != "" {
+		sendForbidden(w, reason)
+		return
+	}
+
+	image, err := utils.ProcessImage(file, header, h.imageConfig)
+	if err != nil {
+		fmt.Printf("❌ Image processing failed: %v\n", err)
+		models.SendError(w, http.StatusBadRequest, "Failed to process image", err.Error())
+		return
+	}
+
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	blog, err = h.store.GetBlogBySlug(slug)
+	if err != nil {
+		sendMediaError(w, "Failed to upload media", err)
+		return
+	}
+
+	media := blog.MediaItems()
+	if len(media) >= models.MaxMediaItems {
+		models.SendError(w, http.StatusBadRequest, "Too many media items", fmt.Sprintf("A blog can have at most %d media items", models.MaxMediaItems))
+		return
+	}
+	item, err := h.storeImage(image, media, altText, caption)
+	if err != nil {
+		fmt.Printf("❌ Failed to save media for blog %s: %v\n", blog.Slug, err)
+		models.SendError(w, http.StatusInternalServerError, "Failed to save image", err.Error())
+		return
+	}
+
+	media = append(media, item)
+	req := models.UpdateBlogRequest{Media: &media}
+	if hero {
+		req.SetHeroImage(&item)
+	}
+	updatedBlog, err := h.store.UpdateBlogBySlug(blog.Slug, req)
+	if err != nil {
+		sendMediaError(w, "Failed to upload media", err)
+		return
+	}
+
+	fmt.Printf("🖼️ Added media %s to blog %s\n", item.Filename, updatedBlog.Slug)
+	models.SendSuccess(w, http.StatusCreated, "Media uploaded successfully", item.ToResponse(updatedBlog.Slug, hero))
+}
+
+// UpdateMedia changes a media item's alt text or caption, or whether it is
+// the blog's hero image
+func (h *BlogHandler) UpdateMedia(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	slug := vars["slug"]
+	filename := vars["filename"]
+
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateMediaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		models.SendError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+	if req.AltText == nil && req.Caption == nil && req.Hero == nil {
+		models.SendError(w, http.StatusBadRequest, "No fields to update", "At least one of alt_text, caption or hero must be provided")
+		return
+	}
+
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	blog, err := h.store.GetBlogBySlug(slug)
+	if err != nil {
+		sendMediaError(w, "Failed to update media", err)
+		return
+	}
+	if reason := authorizeBlogUpdate(user, blog, models.UpdateBlogRequest{}); reason != "" {
+		sendForbidden(w, reason)
+		return
+	}
+
+	media := blog.MediaItems()
+	i := findMediaItem(media, filename)
+	if i < 0 {
+		sendMediaError(w, "Failed to update media", errors.New("media not found"))
+		return
+	}
+
+	item := &media[i]
+	if req.AltText != nil {
+		item.AltText = *req.AltText
+	}
+	if req.Caption != nil {
+		item.Caption = *req.Caption
+	}
+	if err := models.ValidateMediaText(item.AltText, item.Caption); err != nil {
+		models.SendError(w, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	updates := models.UpdateBlogRequest{Media: &media}
+	hero := blog.Image == filename
+	if req.Hero != nil && *req.Hero != hero {
+		hero = *req.Hero
+		if hero {
+			updates.SetHeroImage(item)
+		} else {
+			updates.SetHeroImage(nil)
+		}
+	}
+
+	updatedBlog, err := h.store.UpdateBlogBySlug(blog.Slug, updates)
+	if err != nil {
+		sendMediaError(w, "Failed to update media", err)
+		return
+	}
+
+	models.SendSuccess(w, http.StatusOK, "Media updated successfully", item.ToResponse(updatedBlog.Slug, hero))
+}
+
+// DeleteMedia removes an item from a blog's media collection. Deleting the
+// hero image leaves the blog without one. The item's library asset stays
+// until garbage collection finds no post using it.
+func (h *BlogHandler) DeleteMedia(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	slug := vars["slug"]
+	filename := vars["filename"]
+
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	blog, err := h.store.GetBlogBySlug(slug)
+	if err != nil {
+		sendMediaError(w, "Failed to delete media", err)
+		return
+	}
+	if reason := authorizeBlogUpdate(user, blog, models.UpdateBlogRequest{}); reason != "" {
+		sendForbidden(w, reason)
+		return
+	}
+
+	media := blog.MediaItems()
+	if findMediaItem(media, filename) < 0 {
+		sendMediaError(w, "Failed to delete media", errors.New("media not found"))
+		return
+	}
+
+	// Files stored with the blog itself are deleted once it is saved
+	media = withoutMedia(media, filename)
+	updates := models.UpdateBlogRequest{Media: &media}
+	if blog.Image == filename {
+		updates.SetHeroImage(nil)
+	}
+	if _, err := h.store.UpdateBlogBySlug(blog.Slug, updates); err != nil {
+		sendMediaError(w, "Failed to delete media", err)
+		return
+	}
+
+	fmt.Printf("🗑️ Deleted media %s from blog %s\n", filename, blog.Slug)
+	models.SendSuccess(w, http.StatusOK, "Media deleted successfully", nil)
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go-react-backend/models"
+	"go-react-backend/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// mediaLibrary returns the store's media library, sending a 501 response
+// when the configured store has none
+func (h *BlogHandler) mediaLibrary(w http.ResponseWriter) (models.MediaLibrary, bool) {
+	library, ok := h.store.(models.MediaLibrary)
+	if !ok {
+		models.SendError(w, http.StatusNotImplemented, "Media library not supported", "The configured blog store has no media library")
+		return nil, false
+	}
+	return library, true
+}
+
+// sendLibraryError maps media library errors to HTTP responses
+func sendLibraryError(w http.ResponseWriter, message string, err error) {
+	switch err.Error() {
+	case "asset not found":
+		models.SendError(w, http.StatusNotFound, "Asset not found", err.Error())
+	case "image not found":
+		models.SendError(w, http.StatusNotFound, "Image file not found", err.Error())
+	case "media library not supported":
+		models.SendError(w, http.StatusNotImplemented, "Media library not supported", err.Error())
+	default:
+		models.SendError(w, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+// ListMediaAssets lists every asset in the media library, newest first, with
+// the number of posts using each
+func (h *BlogHandler) ListMediaAssets(w http.ResponseWriter, r *http.Request) {
+	library, ok := h.mediaLibrary(w)
+	if !ok {
+		return
+	}
+
+	assets, err := library.ListAssets()
+	if err != nil {
+		sendLibraryError(w, "Failed to list media", err)
+		return
+	}
+
+	responses := make([]models.MediaAssetResponse, 0, len(assets))
+	for i := range assets {
+		responses = append(responses, assets[i].ToResponse())
+	}
+	models.SendSuccess(w, http.StatusOK, "Media retrieved successfully", responses)
+}
+
+// GetMediaAssetUsage lists the posts, live or in the trash, that use an asset
+func (h *BlogHandler) GetMediaAssetUsage(w http.ResponseWriter, r *http.Request) {
+	library, ok := h.mediaLibrary(w)
+	if !ok {
+		return
+	}
+
+	usage, err := library.AssetUsage(mux.Vars(r)["hash"])
+	if err != nil {
+		sendLibraryError(w, "Failed to get media usage", err)
+		return
+	}
+
+	responses := make([]models.MediaUsageResponse, 0, len(usage))
+	for i := range usage {
+		responses = append(responses, usage[i].ToResponse())
+	}
+	models.SendSuccess(w, http.StatusOK, "Media usage retrieved successfully", responses)
+}
+
+// ServeMediaAsset serves one of a library asset's files. Asset files never
+// change, so they may be cached for good.
+func (h *BlogHandler) ServeMediaAsset(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	hash := vars["hash"]
+	filename := vars["filename"]
+
+	library, ok := h.mediaLibrary(w)
+	if !ok {
+		return
+	}
+
+	data, err := library.GetAssetFile(hash, filename)
+	if err != nil {
+		sendLibraryError(w, "Failed to get media", err)
+		return
+	}
+
+	// Library files are always named after their format
+	w.Header().Set("Content-Type", utils.MimeTypeForFilename(filename))
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("ETag", fmt.Sprintf("\"%s-%s\"", hash, filename))
+	http.ServeContent(w, r, filename, time.Time{}, bytes.NewReader(data))
+}
+
+// CollectMediaGarbage deletes the library assets no post uses, except those
+// uploaded within the grace period
+func (h *BlogHandler) CollectMediaGarbage(w http.ResponseWriter, r *http.Request) {
+	library, ok := h.mediaLibrary(w)
+	if !ok {
+		return
+	}
+
+	deleted, err := library.CollectGarbage(time.Now().Add(-models.MediaGCGracePeriod))
+	if err != nil {
+		sendLibraryError(w, "Failed to collect unused media", err)
+		return
+	}
+
+	fmt.Printf("🗑️ Deleted %d unused media asset(s)\n", deleted)
+	models.SendSuccess(w, http.StatusOK, "Unused media deleted", models.MediaGCResponse{Deleted: deleted})
+}
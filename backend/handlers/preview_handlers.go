@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go-react-backend/auth"
+	"go-react-backend/models"
+
+	"github.com/gorilla/mux"
+)
+
+// PreviewHandler handles draft preview link requests
+type PreviewHandler struct {
+	store  models.BlogStore
+	signer *auth.PreviewSigner
+}
+
+// NewPreviewHandler creates a new preview handler
+func NewPreviewHandler(store models.BlogStore, signer *auth.PreviewSigner) *PreviewHandler {
+	return &PreviewHandler{store: store, signer: signer}
+}
+
+// CreatePreviewLink creates a signed link that shows the blog's page to
+// anyone holding it, published or not, until it expires. The request body
+// is optional.
+func (h *PreviewHandler) CreatePreviewLink(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CreatePreviewLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		models.SendError(w, http.StatusBadRequest, "Invalid JSON", err.Error())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		models.SendError(w, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	blog, err := h.store.GetBlogBySlug(slug)
+	if err != nil {
+		if err.Error() == "blog not found" {
+			models.SendError(w, http.StatusNotFound, "Blog not found", err.Error())
+		} else {
+			models.SendError(w, http.StatusInternalServerError, "Failed to create preview link", err.Error())
+		}
+		return
+	}
+	if !user.CanEditBlog(blog) {
+		sendForbidden(w, "Your role can only share previews of your own blogs")
+		return
+	}
+
+	expires := time.Now().Add(req.TTL())
+	token := h.signer.Sign(blog.ID, expires)
+	models.SendSuccess(w, http.StatusCreated, "Preview link created successfully", models.PreviewLinkResponse{
+		URL:       "/blogs/" + url.PathEscape(blog.Slug) + "?preview=" + url.QueryEscape(token),
+		Token:     token,
+		ExpiresAt: expires.UTC().Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"go-react-backend/models"
+	"go-react-backend/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// revisionStore returns the store's revision support, sending a 501 response
+// when the configured store does not keep revisions
+func (h *BlogHandler) revisionStore(w http.ResponseWriter) (models.RevisionStore, bool) {
+	revisions, ok := h.store.(models.RevisionStore)
+	if !ok {
+		models.SendError(w, http.StatusNotImplemented, "Revisions not supported", "The configured blog store does not keep revisions")
+		return nil, false
+	}
+	return revisions, true
+}
+
+// requireEditableBlog sends an error response unless the blog with slug
+// exists and the signed-in caller may edit it. Revisions hold earlier drafts
+// of a blog, so only its editors may read them.
+func (h *BlogHandler) requireEditableBlog(w http.ResponseWriter, r *http.Request, slug string) bool {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return false
+	}
+
+	blog, err := h.store.GetBlogBySlug(slug)
+	if err != nil {
+		sendRevisionError(w, "Failed to get blog", err)
+		return false
+	}
+	if reason := authorizeBlogUpdate(user, blog, models.UpdateBlogRequest{}); reason != "" {
+		sendForbidden(w, reason)
+		return false
+	}
+	return true
+}
+
+// sendRevisionError maps revision store errors to HTTP responses
+func sendRevisionError(w http.ResponseWriter, message string, err error) {
+	switch err.Error() {
+	case "blog not found":
+		models.SendError(w, http.StatusNotFound, "Blog not found", err.Error())
+	case "revision not found":
+		models.SendError(w, http.StatusNotFound, "Revision not found", err.Error())
+	case "slug already exists":
+		models.SendError(w, http.StatusConflict, "Slug already exists", err.Error())
+	case "revisions not supported":
+		models.SendError(w, http.StatusNotImplemented, "Revisions not supported", err.Error())
+	default:
+		models.SendError(w, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+// ListRevisions lists the revision history of a blog, newest first
+func (h *BlogHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+
+	if !h.requireEditableBlog(w, r, slug) {
+		return
+	}
+
+	revisionStore, ok := h.revisionStore(w)
+	if !ok {
+		return
+	}
+
+	revisions, err := revisionStore.ListRevisions(slug)
+	if err != nil {
+		sendRevisionError(w, "Failed to list revisions", err)
+		return
+	}
+
+	// Listings leave out the previous content; fetch a revision for that
+	responses := make([]models.RevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		responses = append(responses, revision.ToResponse(false))
+	}
+
+	models.SendSuccess(w, http.StatusOK, "Revisions retrieved successfully", responses)
+}
+
+// GetRevision returns a single revision including the previous blog state
+func (h *BlogHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	slug := vars["slug"]
+
+	revisionID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.SendError(w, http.StatusBadRequest, "Invalid revision ID", err.Error())
+		return
+	}
+
+	if !h.requireEditableBlog(w, r, slug) {
+		return
+	}
+
+	revisionStore, ok := h.revisionStore(w)
+	if !ok {
+		return
+	}
+
+	revision, err := revisionStore.GetRevision(slug, revisionID)
+	if err != nil {
+		sendRevisionError(w, "Failed to get revision", err)
+		return
+	}
+
+	models.SendSuccess(w, http.StatusOK, "Revision retrieved successfully", revision.ToResponse(true))
+}
+
+// DiffRevisions returns a unified diff of the content between two versions
+// of a blog. The from and to query parameters take a revision ID or
+// "current"; from defaults to the latest revision and to to "current".
+func (h *BlogHandler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+
+	if !h.requireEditableBlog(w, r, slug) {
+		return
+	}
+
+	revisionStore, ok := h.revisionStore(w)
+	if !ok {
+		return
+	}
+
+	from := r.URL.Query().Get("from")
+	to := r.URL.Query().Get("to")
+	if to == "" {
+		to = "current"
+	}
+	if from == "" {
+		revisions, err := revisionStore.ListRevisions(slug)
+		if err != nil {
+			sendRevisionError(w, "Failed to diff revisions", err)
+			return
+		}
+		if len(revisions) == 0 {
+			models.SendError(w, http.StatusNotFound, "Revision not found", "Blog has no revisions")
+			return
+		}
+		from = strconv.Itoa(revisions[0].ID)
+	}
+
+	fromBlog, err := h.blogVersion(revisionStore, slug, from)
+	if err != nil {
+		sendRevisionError(w, "Failed to diff revisions", err)
+		return
+	}
+	toBlog, err := h.blogVersion(revisionStore, slug, to)
+	if err != nil {
+		sendRevisionError(w, "Failed to diff revisions", err)
+		return
+	}
+
+	if err := utils.CheckDiffSize(fromBlog.Content, toBlog.Content); err != nil {
+		models.SendError(w, http.StatusRequestEntityTooLarge, "Diff too large", err.Error())
+		return
+	}
+
+	diff := utils.UnifiedDiff(
+		fmt.Sprintf("%s/content.md (%s)", fromBlog.Slug, versionLabel(from)),
+		fmt.Sprintf("%s/content.md (%s)", toBlog.Slug, versionLabel(to)),
+		fromBlog.Content, toBlog.Content, 3)
+
+	models.SendSuccess(w, http.StatusOK, "Diff generated successfully", models.RevisionDiffResponse{
+		From:          from,
+		To:            to,
+		ChangedFields: models.ChangedFields(*fromBlog, *toBlog),
+		Diff:          diff,
+	})
+}
+
+// blogVersion resolves a version ("current" or a revision ID) of a blog
+func (h *BlogHandler) blogVersion(revisionStore models.RevisionStore, slug string, version string) (*models.Blog, error) {
+	if version == "current" {
+		return h.store.GetBlogBySlug(slug)
+	}
+
+	revisionID, err := strconv.Atoi(version)
+	if err != nil {
+		return nil, errors.New("revision not found")
+	}
+
+	revision, err := revisionStore.GetRevision(slug, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	return &revision.Previous, nil
+}
+
+// versionLabel describes a version for diff headers
+func versionLabel(version string) string {
+	if version == "current" {
+		return "current"
+	}
+	return "revision " + version
+}
+
+// RestoreRevision rolls a blog back to the state recorded in a revision. Like
+// other updates, it needs the blog's current ETag in If-Match.
+func (h *BlogHandler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	slug := vars["slug"]
+
+	revisionID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		models.SendError(w, http.StatusBadRequest, "Invalid revision ID", err.Error())
+		return
+	}
+
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	revisionStore, ok := h.revisionStore(w)
+	if !ok {
+		return
+	}
+
+	// Restoring is an update, so it needs the same permissions and mustn't
+	// slip in between another request's version check and write
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	currentBlog, err := h.store.GetBlogBySlug(slug)
+	if err != nil {
+		sendRevisionError(w, "Failed to restore revision", err)
+		return
+	}
+	revision, err := revisionStore.GetRevision(slug, revisionID)
+	if err != nil {
+		sendRevisionError(w, "Failed to restore revision", err)
+		return
+	}
+	if reason := authorizeBlogUpdate(user, currentBlog, revision.RestoreRequest(currentBlog)); reason != "" {
+		sendForbidden(w, reason)
+		return
+	}
+	if !checkIfMatch(w, r, currentBlog) {
+		return
+	}
+
+	restoredBlog, err := revisionStore.RestoreRevision(slug, revisionID)
+	if err != nil {
+		sendRevisionError(w, "Failed to restore revision", err)
+		return
+	}
+
+	setETag(w, restoredBlog)
+	models.SendSuccess(w, http.StatusOK, "Revision restored successfully", restoredBlog.ToResponse())
+}
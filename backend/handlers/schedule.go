@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"go-react-backend/models"
+)
+
+// scheduleLayouts are the accepted formats for publish_at and unpublish_at.
+// The second is what HTML datetime-local inputs send and is read as UTC.
+var scheduleLayouts = []string{time.RFC3339, "2006-01-02T15:04"}
+
+// formSchedule reads a publish_at or unpublish_at form field. The second
+// result reports whether the field was sent at all; an empty value yields the
+// zero time, which clears the schedule on update.
+func formSchedule(r *http.Request, field string) (time.Time, bool, error) {
+	values, ok := r.MultipartForm.Value[field]
+	if !ok || len(values) == 0 {
+		return time.Time{}, false, nil
+	}
+	if values[0] == "" {
+		return time.Time{}, true, nil
+	}
+
+	for _, layout := range scheduleLayouts {
+		if t, err := time.Parse(layout, values[0]); err == nil {
+			return t.UTC(), true, nil
+		}
+	}
+	return time.Time{}, true, &models.ValidationError{
+		Field:   field,
+		Message: fmt.Sprintf("%s must be an RFC 3339 timestamp, got %q", field, values[0]),
+	}
+}
+
+// formScheduleCreate reads a schedule field for a new blog, where an empty
+// or missing value means no schedule
+func formScheduleCreate(r *http.Request, field string) (*time.Time, error) {
+	t, ok, err := formSchedule(r, field)
+	if err != nil || !ok || t.IsZero() {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// formScheduleUpdate reads a schedule field for an update request, which is
+// left nil when the field wasn't sent
+func formScheduleUpdate(r *http.Request, field string) (*time.Time, error) {
+	t, ok, err := formSchedule(r, field)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// scheduleChanges reports whether updates changes either of blog's schedules
+func scheduleChanges(blog *models.Blog, updates models.UpdateBlogRequest) bool {
+	scheduled := *blog
+	models.ApplySchedule(&scheduled.PublishAt, updates.PublishAt)
+	models.ApplySchedule(&scheduled.UnpublishAt, updates.UnpublishAt)
+	changed := models.ChangedFields(*blog, scheduled)
+	return len(changed) > 0
+}
+
+// validateScheduleUpdate checks the schedule blog would have after updates
+func validateScheduleUpdate(blog *models.Blog, updates models.UpdateBlogRequest) error {
+	scheduled := *blog
+	models.ApplySchedule(&scheduled.PublishAt, updates.PublishAt)
+	models.ApplySchedule(&scheduled.UnpublishAt, updates.UnpublishAt)
+	return models.ValidateSchedule(scheduled.PublishAt, scheduled.UnpublishAt)
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-react-backend/models"
+	"go-react-backend/search"
+)
+
+// SearchHandler handles full-text search requests
+type SearchHandler struct {
+	index *search.Index
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(index *search.Index) *SearchHandler {
+	return &SearchHandler{index: index}
+}
+
+// Search finds published blogs matching the q query parameter, paginated
+// with page and per_page like blog listings
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := strings.TrimSpace(params.Get("q"))
+	if query == "" {
+		models.SendError(w, http.StatusBadRequest, "Invalid query parameters", "q is required")
+		return
+	}
+
+	page, perPage := 1, defaultBlogsPerPage
+	if value := params.Get("page"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxBlogPage {
+			models.SendError(w, http.StatusBadRequest, "Invalid query parameters", fmt.Sprintf("page must be between 1 and %d", maxBlogPage))
+			return
+		}
+		page = parsed
+	}
+	if value := params.Get("per_page"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxBlogsPerPage {
+			models.SendError(w, http.StatusBadRequest, "Invalid query parameters", fmt.Sprintf("per_page must be between 1 and %d", maxBlogsPerPage))
+			return
+		}
+		perPage = parsed
+	}
+
+	results, total := h.index.Search(query, search.Options{
+		Offset: (page - 1) * perPage,
+		Limit:  perPage,
+	})
+
+	responses := make([]models.SearchResultResponse, 0, len(results))
+	for _, result := range results {
+		blog := result.Blog
+		tags := blog.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		responses = append(responses, models.SearchResultResponse{
+			ID:              blog.ID.String(),
+			Slug:            blog.Slug,
+			Title:           blog.Title,
+			MetaDescription: blog.MetaDescription,
+			Image:           blog.Image,
+			AuthorName:      blog.AuthorName,
+			Tags:            tags,
+			Category:        blog.Category,
+			Created:         blog.Created.Format(time.RFC3339),
+			Snippet:         result.Snippet,
+			Score:           result.Score,
+		})
+	}
+
+	models.SendSuccess(w, http.StatusOK, "Search completed successfully", models.SearchResponse{
+		Query:      query,
+		Results:    responses,
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: (total + perPage - 1) / perPage,
+	})
+}
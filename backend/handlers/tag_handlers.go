@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-react-backend/models"
+)
+
+// GetTags lists the tags of live blogs with the number of blogs using each,
+// most used first
+func (h *BlogHandler) GetTags(w http.ResponseWriter, r *http.Request) {
+	blogs, err := h.store.GetAllBlogs()
+	if err != nil {
+		fmt.Printf("❌ Failed to list tags: %v\n", err)
+		models.SendError(w, http.StatusInternalServerError, "Failed to list tags", err.Error())
+		return
+	}
+
+	now := time.Now()
+	published := make([]models.Blog, 0, len(blogs))
+	for _, blog := range blogs {
+		if blog.IsLive(now) {
+			published = append(published, blog)
+		}
+	}
+
+	models.SendSuccess(w, http.StatusOK, "Tags retrieved successfully", models.CountTags(published))
+}
+
+// formTags reads the tags form field, given either as comma-separated text or
+// as repeated fields. The second result reports whether the field was sent at
+// all, so an empty value can clear a blog's tags.
+func formTags(r *http.Request) ([]string, bool) {
+	values, ok := r.MultipartForm.Value["tags"]
+	if !ok {
+		return nil, false
+	}
+
+	var tags []string
+	for _, value := range values {
+		tags = append(tags, strings.Split(value, ",")...)
+	}
+	return models.NormalizeTags(tags), true
+}
+
+// formCategory reads the category form field. The second result reports
+// whether the field was sent at all, so an empty value can clear it.
+func formCategory(r *http.Request) (string, bool) {
+	values, ok := r.MultipartForm.Value["category"]
+	if !ok || len(values) == 0 {
+		return "", false
+	}
+	return models.NormalizeCategory(values[0]), true
+}
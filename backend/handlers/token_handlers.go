@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go-react-backend/auth"
+	"go-react-backend/models"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ListAPITokens lists the signed-in user's API tokens
+func (h *AuthHandler) ListAPITokens(w http.ResponseWriter, r *http.Request) {
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		models.SendError(w, http.StatusUnauthorized, "Authentication required", "")
+		return
+	}
+
+	tokens, err := h.auth.ListAPITokens(user.ID)
+	if err != nil {
+		models.SendError(w, http.StatusInternalServerError, "Failed to list API tokens", err.Error())
+		return
+	}
+
+	responses := make([]models.APITokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		responses = append(responses, token.ToResponse())
+	}
+
+	models.SendSuccess(w, http.StatusOK, "API tokens retrieved successfully", responses)
+}
+
+// CreateAPIToken creates an API token for the signed-in user. The token is
+// only included in this response.
+func (h *AuthHandler) CreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		models.SendError(w, http.StatusUnauthorized, "Authentication required", "")
+		return
+	}
+
+	var req models.CreateAPITokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.SendError(w, http.StatusBadRequest, "Invalid JSON", err.Error())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		models.SendError(w, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	secret, token, err := h.auth.CreateAPIToken(user, req.Name, req.Scopes)
+	if err != nil {
+		fmt.Printf("❌ Failed to create API token: %v\n", err)
+		models.SendError(w, http.StatusInternalServerError, "Failed to create API token", err.Error())
+		return
+	}
+
+	response := token.ToResponse()
+	response.Token = secret
+	models.SendSuccess(w, http.StatusCreated, "API token created successfully", response)
+}
+
+// RevokeAPIToken deletes one of the signed-in user's API tokens
+func (h *AuthHandler) RevokeAPIToken(w http.ResponseWriter, r *http.Request) {
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		models.SendError(w, http.StatusUnauthorized, "Authentication required", "")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		models.SendError(w, http.StatusBadRequest, "Invalid token ID", err.Error())
+		return
+	}
+
+	if err := h.auth.RevokeAPIToken(user.ID, id); err != nil {
+		if err.Error() == "token not found" {
+			models.SendError(w, http.StatusNotFound, "API token not found", err.Error())
+		} else {
+			models.SendError(w, http.StatusInternalServerError, "Failed to revoke API token", err.Error())
+		}
+		return
+	}
+
+	models.SendSuccess(w, http.StatusOK, "API token revoked successfully", nil)
+}
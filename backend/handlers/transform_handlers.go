@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-react-backend/auth"
+	"go-react-backend/models"
+	"go-react-backend/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// TransformHandler handles signed image transformation requests, which
+// resize or crop media library assets on the fly
+type TransformHandler struct {
+	store   models.BlogStore
+	signer  *auth.ImageSigner
+	cache   *utils.ImageCache
+	quality int
+}
+
+// NewTransformHandler creates a new transform handler that keeps generated
+// images in cache and encodes JPEGs at the quality imageConfig sets
+func NewTransformHandler(store models.BlogStore, signer *auth.ImageSigner, cache *utils.ImageCache, imageConfig utils.ImageConfig) *TransformHandler {
+	return &TransformHandler{store: store, signer: signer, cache: cache, quality: imageConfig.Quality}
+}
+
+// mediaLibrary returns the store's media library, sending a 501 response
+// when the configured store has none
+func (h *TransformHandler) mediaLibrary(w http.ResponseWriter) (models.MediaLibrary, bool) {
+	library, ok := h.store.(models.MediaLibrary)
+	if !ok {
+		models.SendError(w, http.StatusNotImplemented, "Media library not supported", "The configured blog store has no media library")
+		return nil, false
+	}
+	return library, true
+}
+
+// CreateTransformURL signs a transformation of a media library asset and
+// returns the URL that serves it
+func (h *TransformHandler) CreateTransformURL(w http.ResponseWriter, r *http.Request) {
+	hash := mux.Vars(r)["hash"]
+
+	var req models.CreateImageTransformRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		models.SendError(w, http.StatusBadRequest, "Invalid JSON", err.Error())
+		return
+	}
+	opts := utils.TransformOptions{Width: req.Width, Height: req.Height, Fit: req.Fit, Format: req.Format}.WithDefaults()
+	if err := opts.Validate(); err != nil {
+		models.SendError(w, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	library, ok := h.mediaLibrary(w)
+	if !ok {
+		return
+	}
+	if _, err := library.GetAsset(hash); err != nil {
+		sendLibraryError(w, "Failed to create transform URL", err)
+		return
+	}
+
+	query := opts.Query()
+	models.SendSuccess(w, http.StatusCreated, "Transform URL created successfully", models.ImageTransformResponse{
+		URL:    models.TransformURL(hash, query, h.signer.Sign(hash, query)),
+		Width:  opts.Width,
+		Height: opts.Height,
+		Fit:    opts.Fit,
+		Format: opts.Format,
+	})
+}
+
+// ServeTransform serves a media library asset transformed as its signed
+// query parameters (w, h, fit, format and the signature s) describe.
+// Results are cached on disk and, since assets never change, by browsers
+// for good.
+func (h *TransformHandler) ServeTransform(w http.ResponseWriter, r *http.Request) {
+	hash := mux.Vars(r)["hash"]
+	query := r.URL.Query()
+
+	opts := utils.TransformOptions{Fit: query.Get("fit"), Format: query.Get("format")}.WithDefaults()
+	var err error
+	if opts.Width, err = parseDimension(query.Get("w")); err != nil {
+		models.SendError(w, http.StatusBadRequest, "Invalid width", err.Error())
+		return
+	}
+	if opts.Height, err = parseDimension(query.Get("h")); err != nil {
+		models.SendError(w, http.StatusBadRequest, "Invalid height", err.Error())
+		return
+	}
+
+	// The signature covers the parameters in their canonical form
+	if err := h.signer.Verify(hash, opts.Query(), query.Get("s")); err != nil {
+		models.SendError(w, http.StatusForbidden, "Invalid signature", err.Error())
+		return
+	}
+	if err := opts.Validate(); err != nil {
+		models.SendError(w, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	key := transformCacheKey(hash, opts, h.quality)
+	data, cached := h.cache.Get(key)
+	if !cached {
+		library, ok := h.mediaLibrary(w)
+		if !ok {
+			return
+		}
+		asset, err := library.GetAsset(hash)
+		if err != nil {
+			sendLibraryError(w, "Failed to transform image", err)
+			return
+		}
+		source, err := library.GetAssetFile(hash, asset.SourceFile())
+		if err != nil {
+			sendLibraryError(w, "Failed to transform image", err)
+			return
+		}
+
+		transformed, err := utils.TransformImage(source, opts, h.quality)
+		if err != nil {
+			fmt.Printf("❌ Image transform failed for %s: %v\n", hash, err)
+			models.SendError(w, http.StatusInternalServerError, "Failed to transform image", err.Error())
+			return
+		}
+		data = transformed.Data
+
+		if err := h.cache.Put(key, data); err != nil {
+			fmt.Printf("⚠️ Failed to cache transformed image: %v\n", err)
+		}
+	}
+
+	// Cached files carry no extension, so the type comes from their content
+	w.Header().Set("Content-Type", http.DetectContentType(data))
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("ETag", "\""+key+"\"")
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+}
+
+// parseDimension parses a width or height query parameter, where "" means 0
+func parseDimension(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a whole number", value)
+	}
+	return n, nil
+}
+
+// transformCacheKey names the cached result of transforming the asset with
+// hash. The JPEG quality is part of it so changing the setting doesn't serve
+// stale results.
+func transformCacheKey(hash string, opts utils.TransformOptions, quality int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%d", hash, opts.Query(), quality)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-react-backend/auth"
+	"go-react-backend/models"
+	"go-react-backend/storage"
+	"go-react-backend/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// newTestTransformHandler returns a handler over a fresh file store holding
+// two 40x20 PNG assets, whose hashes it also returns
+func newTestTransformHandler(t *testing.T) (*TransformHandler, *auth.ImageSigner, string, string) {
+	t.Helper()
+	store, err := storage.NewFileBlogStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileBlogStore: %v", err)
+	}
+	var source bytes.Buffer
+	if err := png.Encode(&source, image.NewRGBA(image.Rect(0, 0, 40, 20))); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+	var hashes []string
+	for _, c := range []string{"a", "b"} {
+		asset := models.MediaAsset{Hash: strings.Repeat(c, 64), Filename: "image.png", MimeType: "image/png", Width: 40, Height: 20}
+		if _, err := store.SaveAsset(asset, map[string][]byte{"image.png": source.Bytes()}); err != nil {
+			t.Fatalf("SaveAsset: %v", err)
+		}
+		hashes = append(hashes, asset.Hash)
+	}
+
+	signer, err := auth.NewImageSigner([]byte(strings.Repeat("s", auth.MinPreviewSecretLength)))
+	if err != nil {
+		t.Fatalf("NewImageSigner: %v", err)
+	}
+	cache, err := utils.NewImageCache(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatalf("NewImageCache: %v", err)
+	}
+	return NewTransformHandler(store, signer, cache, utils.DefaultImageConfig()), signer, hashes[0], hashes[1]
+}
+
+func TestServeTransformSignature(t *testing.T) {
+	handler, signer, hash, otherHash := newTestTransformHandler(t)
+	query := "w=20&h=0&fit=contain&format=png"
+	signature := signer.Sign(hash, query)
+
+	otherSigner, err := auth.NewImageSigner([]byte(strings.Repeat("o", auth.MinPreviewSecretLength)))
+	if err != nil {
+		t.Fatalf("NewImageSigner: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		hash       string
+		query      string
+		wantStatus int
+	}{
+		{"as signed", hash, models.TransformURL(hash, query, signature), http.StatusOK},
+		{"defaults left out", hash, "?w=20&format=png&s=" + signature, http.StatusOK},
+		{"parameters reordered", hash, "?format=png&fit=contain&h=0&w=20&s=" + signature, http.StatusOK},
+		{"width changed", hash, "?w=40&h=0&fit=contain&format=png&s=" + signature, http.StatusForbidden},
+		{"height added", hash, "?w=20&h=10&fit=contain&format=png&s=" + signature, http.StatusForbidden},
+		{"fit changed", hash, "?w=20&h=0&fit=cover&format=png&s=" + signature, http.StatusForbidden},
+		{"format changed", hash, "?w=20&h=0&fit=contain&format=jpeg&s=" + signature, http.StatusForbidden},
+		{"another asset", otherHash, "?" + query + "&s=" + signature, http.StatusForbidden},
+		{"no signature", hash, "?" + query, http.StatusForbidden},
+		{"garbled signature", hash, "?" + query + "&s=not-base64!", http.StatusForbidden},
+		{"signature cut short", hash, "?" + query + "&s=" + signature[:len(signature)-2], http.StatusForbidden},
+		{"signed with another secret", hash, "?" + query + "&s=" + otherSigner.Sign(hash, query), http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Only the query matters; TransformURL's path is dropped
+			rawQuery := tt.query[strings.Index(tt.query, "?"):]
+			req := httptest.NewRequest(http.MethodGet, "/api/media/"+tt.hash+"/transform"+rawQuery, nil)
+			req = mux.SetURLVars(req, map[string]string{"hash": tt.hash})
+			rec := httptest.NewRecorder()
+
+			handler.ServeTransform(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			img, err := png.Decode(rec.Body)
+			if err != nil {
+				t.Fatalf("png.Decode: %v", err)
+			}
+			if bounds := img.Bounds(); bounds.Dx() != 20 || bounds.Dy() != 10 {
+				t.Errorf("transformed to %dx%d, want 20x10", bounds.Dx(), bounds.Dy())
+			}
+		})
+	}
+}
+
+func TestServeTransformRejectsBeforeTransforming(t *testing.T) {
+	handler, _, hash, _ := newTestTransformHandler(t)
+
+	// An unsigned request for a huge image is refused without doing the work
+	req := httptest.NewRequest(http.MethodGet, "/api/media/"+hash+"/transform?w=4000&h=4000&fit=cover&s=x", nil)
+	req = mux.SetURLVars(req, map[string]string{"hash": hash})
+	rec := httptest.NewRecorder()
+	handler.ServeTransform(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if count, _ := handler.cache.Size(); count != 0 {
+		t.Errorf("cache holds %d images after a refused request, want none", count)
+	}
+}
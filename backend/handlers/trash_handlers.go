@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-react-backend/models"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// trashStore returns the store's trash support, sending a 501 response when
+// the configured store deletes blogs permanently
+func (h *BlogHandler) trashStore(w http.ResponseWriter) (models.TrashStore, bool) {
+	trash, ok := h.store.(models.TrashStore)
+	if !ok {
+		models.SendError(w, http.StatusNotImplemented, "Trash not supported", "The configured blog store does not keep deleted blogs")
+		return nil, false
+	}
+	return trash, true
+}
+
+// ListTrash lists the deleted blogs the user may restore, most recently
+// deleted first
+func (h *BlogHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	trash, ok := h.trashStore(w)
+	if !ok {
+		return
+	}
+
+	trashed, err := trash.ListTrash()
+	if err != nil {
+		models.SendError(w, http.StatusInternalServerError, "Failed to list trash", err.Error())
+		return
+	}
+
+	responses := make([]models.TrashedBlogResponse, 0, len(trashed))
+	for _, item := range trashed {
+		if user.CanDeleteBlog(&item.Blog) {
+			responses = append(responses, item.ToResponse())
+		}
+	}
+
+	models.SendSuccess(w, http.StatusOK, "Trash retrieved successfully", responses)
+}
+
+// RestoreTrashedBlog moves a deleted blog out of the trash
+func (h *BlogHandler) RestoreTrashedBlog(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		models.SendError(w, http.StatusBadRequest, "Invalid blog ID", err.Error())
+		return
+	}
+
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	trash, ok := h.trashStore(w)
+	if !ok {
+		return
+	}
+
+	// Restoring undoes a delete, so it needs the same permissions
+	trashed, err := trash.ListTrash()
+	if err != nil {
+		models.SendError(w, http.StatusInternalServerError, "Failed to restore blog", err.Error())
+		return
+	}
+	for _, item := range trashed {
+		if item.Blog.ID == id {
+			if reason := authorizeBlogDelete(user, &item.Blog); reason != "" {
+				sendForbidden(w, reason)
+				return
+			}
+			break
+		}
+	}
+
+	restoredBlog, err := trash.RestoreTrashedBlog(id)
+	if err != nil {
+		switch err.Error() {
+		case "blog not found in trash":
+			models.SendError(w, http.StatusNotFound, "Blog not found in trash", err.Error())
+		case "slug already exists":
+			models.SendError(w, http.StatusConflict, "Slug already exists", err.Error())
+		case "trash not supported":
+			models.SendError(w, http.StatusNotImplemented, "Trash not supported", err.Error())
+		default:
+			models.SendError(w, http.StatusInternalServerError, "Failed to restore blog", err.Error())
+		}
+		return
+	}
+
+	setETag(w, restoredBlog)
+	models.SendSuccess(w, http.StatusOK, "Blog restored successfully", restoredBlog.ToResponse())
+}
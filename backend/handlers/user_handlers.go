@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go-react-backend/models"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// sendUserError maps auth service errors to HTTP responses
+func sendUserError(w http.ResponseWriter, message string, err error) {
+	if validationErr, ok := err.(*models.ValidationError); ok {
+		models.SendError(w, http.StatusBadRequest, "Validation failed", validationErr.Error())
+		return
+	}
+
+	switch err.Error() {
+	case "user not found":
+		models.SendError(w, http.StatusNotFound, "User not found", err.Error())
+	case "username already exists":
+		models.SendError(w, http.StatusConflict, "Username already exists", err.Error())
+	case "cannot remove the last admin":
+		models.SendError(w, http.StatusConflict, "Cannot remove the last admin", err.Error())
+	default:
+		fmt.Printf("❌ %s: %v\n", message, err)
+		models.SendError(w, http.StatusInternalServerError, message, err.Error())
+	}
+}
+
+// ListUsers lists every user account
+func (h *AuthHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := h.auth.ListUsers()
+	if err != nil {
+		sendUserError(w, "Failed to list users", err)
+		return
+	}
+
+	responses := make([]models.UserResponse, 0, len(users))
+	for _, user := range users {
+		responses = append(responses, user.ToResponse())
+	}
+
+	models.SendSuccess(w, http.StatusOK, "Users retrieved successfully", responses)
+}
+
+// CreateUser creates a user account with a role
+func (h *AuthHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.SendError(w, http.StatusBadRequest, "Invalid JSON", err.Error())
+		return
+	}
+
+	user, err := h.auth.CreateUser(req.Username, req.Password, req.Role)
+	if err != nil {
+		sendUserError(w, "Failed to create user", err)
+		return
+	}
+
+	models.SendSuccess(w, http.StatusCreated, "User created successfully", user.ToResponse())
+}
+
+// UpdateUser changes a user's role and/or password
+func (h *AuthHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		models.SendError(w, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	var req models.UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		models.SendError(w, http.StatusBadRequest, "Invalid JSON", err.Error())
+		return
+	}
+	if req.Role == nil && req.Password == nil {
+		models.SendError(w, http.StatusBadRequest, "No fields to update", "At least one field must be provided")
+		return
+	}
+
+	user, err := h.auth.UpdateUser(id, req)
+	if err != nil {
+		sendUserError(w, "Failed to update user", err)
+		return
+	}
+
+	models.SendSuccess(w, http.StatusOK, "User updated successfully", user.ToResponse())
+}
+
+// DeleteUser removes a user account. Users can't delete themselves.
+func (h *AuthHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		models.SendError(w, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	user, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+	if user.ID == id {
+		models.SendError(w, http.StatusConflict, "Cannot delete yourself", "Ask another admin to delete your account")
+		return
+	}
+
+	if err := h.auth.DeleteUser(id); err != nil {
+		sendUserError(w, "Failed to delete user", err)
+		return
+	}
+
+	models.SendSuccess(w, http.StatusOK, "User deleted successfully", nil)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"go-react-backend/auth"
+	"go-react-backend/models"
+)
+
+// canViewBlog reports whether the request may see blog at now. Live blogs
+// are public; drafts and blogs outside their publishing schedule are only
+// shown to signed-in users who can edit them.
+func canViewBlog(r *http.Request, blog *models.Blog, now time.Time) bool {
+	if blog.IsLive(now) {
+		return true
+	}
+	user, ok := auth.UserFromContext(r.Context())
+	return ok && user.CanEditBlog(blog)
+}
+
+// canPreviewBlog reports whether the request may see blog at now, either as
+// canViewBlog allows or with a preview token for it in the preview query
+// parameter
+func canPreviewBlog(r *http.Request, blog *models.Blog, previewSigner *auth.PreviewSigner, now time.Time) bool {
+	if canViewBlog(r, blog, now) {
+		return true
+	}
+	token := r.URL.Query().Get("preview")
+	return token != "" && previewSigner != nil && previewSigner.Verify(token, blog.ID, now) == nil
+}
+
+// visibleBlogs returns the blogs the request may see
+func visibleBlogs(r *http.Request, blogs []models.Blog) []models.Blog {
+	now := time.Now()
+	visible := make([]models.Blog, 0, len(blogs))
+	for i := range blogs {
+		if canViewBlog(r, &blogs[i], now) {
+			visible = append(visible, blogs[i])
+		}
+	}
+	return visible
+}
@@ -1,21 +1,31 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"html/template"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"go-react-backend/auth"
+	"go-react-backend/feeds"
 	"go-react-backend/handlers"
+	"go-react-backend/markdown"
 	"go-react-backend/middleware"
 	"go-react-backend/models"
 	"go-react-backend/routes"
+	"go-react-backend/search"
 	"go-react-backend/storage"
+	"go-react-backend/utils"
 
 	"github.com/gorilla/mux"
 )
@@ -62,22 +72,209 @@ func main() {
 		dataDir = "data"
 	}
 	
-	blogStore, err := storage.NewFileBlogStore(dataDir)
+	// User accounts and sessions live under the data directory whichever
+	// blog storage backend is selected
+	authStore, err := storage.NewFileAuthStore(dataDir)
 	if err != nil {
-		log.Fatalf("Failed to initialize blog storage: %v", err)
+		log.Fatalf("Failed to initialize auth storage: %v", err)
 	}
+	authService := auth.NewService(authStore, authStore, authStore)
+	
+	// "create-admin" bootstraps an admin account instead of starting the server
+	if len(os.Args) > 1 && os.Args[1] == "create-admin" {
+		runCreateAdmin(authService, os.Args[2:])
+		return
+	}
+	
+	// Select the storage backend: "file" (default) keeps one directory per
+	// blog under dataDir, "sqlite" uses an embedded database
+	storageBackend := os.Getenv("BLOG_STORAGE")
+	if storageBackend == "" {
+		storageBackend = "file"
+	}
+	
+	// Blog change events (e.g. posts edited on disk) are published here
+	blogEvents := storage.NewEventBus()
+	
+	var blogStore models.BlogStore
+	switch storageBackend {
+	case "file":
+		fileStore, err := storage.NewFileBlogStore(dataDir)
+		if err != nil {
+			log.Fatalf("Failed to initialize blog storage: %v", err)
+		}
+		blogStore = fileStore
+		
+		// Pick up posts edited directly in the data directory unless disabled
+		if os.Getenv("BLOG_WATCH") != "false" {
+			watcher, err := storage.NewWatcher(dataDir, blogEvents)
+			if err != nil {
+				log.Fatalf("Failed to watch blog data directory: %v", err)
+			}
+			defer watcher.Close()
+		}
+	case "sqlite":
+		dbPath := os.Getenv("BLOG_DB_PATH")
+		if dbPath == "" {
+			dbPath = filepath.Join(dataDir, "blog.db")
+		}
+		sqliteStore, err := storage.NewSQLiteBlogStore(dbPath)
+		if err != nil {
+			log.Fatalf("Failed to initialize blog storage: %v", err)
+		}
+		defer sqliteStore.Close()
+		blogStore = sqliteStore
+	default:
+		log.Fatalf("Unknown BLOG_STORAGE %q (expected \"file\" or \"sqlite\")", storageBackend)
+	}
+	
+	// Serve reads (API, SSR pages, images lookups) from an in-memory index,
+	// refreshed whenever the underlying data changes
+	cachedStore := storage.NewCachedBlogStore(blogStore)
+	blogEvents.Subscribe(func(storage.ChangeEvent) {
+		cachedStore.Invalidate()
+	})
+	blogStore = cachedStore
+	
+	// Rendered post HTML is kept until the post's content changes
+	contentCache := markdown.NewCache(markdown.NewRenderer())
+	blogEvents.Subscribe(func(event storage.ChangeEvent) {
+		if event.Type == storage.BlogDeleted {
+			contentCache.Forget(event.ID)
+		}
+	})
+	
+	// Full-text search index, kept current by following blog change events.
+	// Writes through the cache publish events too, for every backend.
+	searchIndex := search.NewIndex()
+	indexedBlogs, err := blogStore.GetAllBlogs()
+	if err != nil {
+		log.Fatalf("Failed to build search index: %v", err)
+	}
+	searchIndex.Load(indexedBlogs)
+	search.Follow(searchIndex, blogStore, blogEvents)
+	cachedStore.PublishChanges(blogEvents)
+	fmt.Printf("🔎 Search index built with %d blogs\n", searchIndex.Len())
+	
+	// Permanently remove blogs that have been in the trash longer than the
+	// retention period (30 days unless BLOG_TRASH_RETENTION says otherwise)
+	trashRetention := 30 * 24 * time.Hour
+	if retentionEnv := os.Getenv("BLOG_TRASH_RETENTION"); retentionEnv != "" {
+		parsed, err := time.ParseDuration(retentionEnv)
+		if err != nil {
+			log.Fatalf("Invalid BLOG_TRASH_RETENTION %q: %v", retentionEnv, err)
+		}
+		trashRetention = parsed
+	}
+	if trash, ok := blogStore.(models.TrashStore); ok && trashRetention > 0 {
+		stopPurge := make(chan struct{})
+		defer close(stopPurge)
+		go storage.PurgeTrashPeriodically(trash, trashRetention, time.Hour, stopPurge)
+	}
+	
+	// Delete media library assets that no post, live or trashed, uses any more
+	if library, ok := blogStore.(models.MediaLibrary); ok {
+		stopMediaGC := make(chan struct{})
+		defer close(stopMediaGC)
+		go storage.CollectMediaPeriodically(library, time.Hour, stopMediaGC)
+	}
+	
+	// Draft preview links are signed with BLOG_PREVIEW_SECRET, or with a
+	// random secret kept next to the user accounts
+	previewSecret := []byte(os.Getenv("BLOG_PREVIEW_SECRET"))
+	if len(previewSecret) == 0 {
+		previewSecret, err = authStore.PreviewSecret()
+		if err != nil {
+			log.Fatalf("Failed to load preview secret: %v", err)
+		}
+	}
+	previewSigner, err := auth.NewPreviewSigner(previewSecret)
+	if err != nil {
+		log.Fatalf("Invalid BLOG_PREVIEW_SECRET: %v (use at least %d characters)", err, auth.MinPreviewSecretLength)
+	}
+	
+	// Uploaded images are saved as JPEG, or PNG when they have transparency,
+	// unless BLOG_IMAGE_FORMAT says otherwise
+	imageConfig := utils.DefaultImageConfig()
+	if formatEnv := os.Getenv("BLOG_IMAGE_FORMAT"); formatEnv != "" {
+		imageConfig.Format = strings.ToLower(formatEnv)
+	}
+	if qualityEnv := os.Getenv("BLOG_IMAGE_QUALITY"); qualityEnv != "" {
+		quality, err := strconv.Atoi(qualityEnv)
+		if err != nil {
+			log.Fatalf("Invalid BLOG_IMAGE_QUALITY %q: %v", qualityEnv, err)
+		}
+		imageConfig.Quality = quality
+	}
+	
+	// BLOG_IMAGE_KEEP_ORIGINAL=true also keeps each upload, stripped of its
+	// metadata, so images can be processed again from it later
+	imageConfig.KeepOriginal = os.Getenv("BLOG_IMAGE_KEEP_ORIGINAL") == "true"
+	if err := imageConfig.Validate(); err != nil {
+		log.Fatalf("Invalid image settings: %v", err)
+	}
+	
+	// Signed image transformation URLs share the preview secret. Their
+	// results are cached in BLOG_IMAGE_CACHE_DIR, dropping the least recently
+	// used once they pass BLOG_IMAGE_CACHE_MB megabytes (256 by default).
+	imageSigner, err := auth.NewImageSigner(previewSecret)
+	if err != nil {
+		log.Fatalf("Invalid BLOG_PREVIEW_SECRET: %v (use at least %d characters)", err, auth.MinPreviewSecretLength)
+	}
+	imageCacheDir := os.Getenv("BLOG_IMAGE_CACHE_DIR")
+	if imageCacheDir == "" {
+		imageCacheDir = filepath.Join(dataDir, ".transforms")
+	}
+	imageCacheMB := 256
+	if cacheEnv := os.Getenv("BLOG_IMAGE_CACHE_MB"); cacheEnv != "" {
+		imageCacheMB, err = strconv.Atoi(cacheEnv)
+		if err != nil || imageCacheMB < 0 {
+			log.Fatalf("Invalid BLOG_IMAGE_CACHE_MB %q: use a whole number of megabytes", cacheEnv)
+		}
+	}
+	imageCache, err := utils.NewImageCache(imageCacheDir, int64(imageCacheMB)<<20)
+	if err != nil {
+		log.Fatalf("Failed to initialize image cache: %v", err)
+	}
+	cachedImages, cachedBytes := imageCache.Size()
+	fmt.Printf("🖼️ Image transform cache holds %d files (%d bytes)\n", cachedImages, cachedBytes)
 	
 	// Initialize handlers
-	blogHandler := handlers.NewBlogHandler(blogStore)
+	blogHandler := handlers.NewBlogHandler(blogStore, imageConfig, previewSigner)
+	authHandler := handlers.NewAuthHandler(authService)
+	searchHandler := handlers.NewSearchHandler(searchIndex)
+	previewHandler := handlers.NewPreviewHandler(blogStore, previewSigner)
+	transformHandler := handlers.NewTransformHandler(blogStore, imageSigner, imageCache, imageConfig)
+	
+	// Publish and unpublish blogs at their scheduled times, looking again
+	// whenever blogs change in case a schedule was set or moved. Transitions
+	// take the blog handler's write lock, so they can't interleave with an
+	// editor's conditional update.
+	publishScheduler := storage.NewPublishScheduler(blogStore, blogHandler.WriteLock())
+	blogEvents.Subscribe(func(storage.ChangeEvent) {
+		publishScheduler.Wake()
+	})
+	stopScheduler := make(chan struct{})
+	defer close(stopScheduler)
+	go publishScheduler.Run(stopScheduler)
 	
 	// Load HTML templates
 	templates := template.Must(template.ParseGlob("templates/*.html"))
 	
 	// Setup routes
-	router := routes.SetupRoutes(blogHandler)
+	router := routes.SetupRoutes(blogHandler, authHandler, searchHandler, previewHandler, transformHandler, authService)
+	setupLoginRoutes(router, authService, templates)
+	setupSearchRoutes(router, searchIndex, templates)
+	setupFeedRoutes(router, blogStore)
+	
+	// Only listed origins may call the API from the browser with credentials
+	allowedOrigins := []string{"http://localhost:5173", "http://localhost:3000"}
+	if originsEnv := os.Getenv("CORS_ALLOWED_ORIGINS"); originsEnv != "" {
+		allowedOrigins = strings.Split(originsEnv, ",")
+	}
 	
 	// Apply middleware
-	handler := middleware.SetupCORS()(router)
+	handler := middleware.SetupCORS(allowedOrigins)(router)
 	handler = middleware.LoggingMiddleware(handler)
 	
 	// Serve static files from React build (for production)
@@ -180,7 +377,7 @@ func main() {
 		})
 		
 		// Add server-side rendered routes
-		setupSSRRoutes(router, blogStore, templates, assetInfo)
+		setupSSRRoutes(router, blogStore, contentCache, authService, previewSigner, templates, assetInfo)
 		
 		// Create SPA handler for remaining routes
 		spa := spaHandler{staticPath: staticPath, indexPath: "index.html"}
@@ -211,7 +408,7 @@ func main() {
 	fmt.Printf("🚀 Go server starting on port %s\n", port)
 	fmt.Printf("📡 API available at http://localhost:%s/api\n", port)
 	fmt.Printf("🏥 Health check at http://localhost:%s/api/health\n", port)
-	fmt.Printf("📝 Blog data stored in: %s\n", dataDir)
+	fmt.Printf("📝 Blog data stored in: %s (%s storage)\n", dataDir, storageBackend)
 	
 	log.Fatal(http.ListenAndServe(":"+port, handler))
 }
@@ -252,20 +449,32 @@ func (h spaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 // setupSSRRoutes configures server-side rendered routes
-func setupSSRRoutes(router *mux.Router, blogStore models.BlogStore, templates *template.Template, assetInfo *AssetInfo) {
-	// Home page with all blogs
+func setupSSRRoutes(router *mux.Router, blogStore models.BlogStore, contentCache *markdown.Cache, authService *auth.Service, previewSigner *auth.PreviewSigner, templates *template.Template, assetInfo *AssetInfo) {
+	// Editor pages send visitors without a session to the login page
+	requireLogin := middleware.RequireAuthPage(authService)
+	
+	// Home page with all live blogs
 	router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		// Only handle GET requests for the root path
 		if r.Method != "GET" || r.URL.Path != "/" {
 			return
 		}
 		
-		blogs, err := blogStore.GetAllBlogs()
+		allBlogs, err := blogStore.GetAllBlogs()
 		if err != nil {
 			http.Error(w, "Failed to fetch blogs", http.StatusInternalServerError)
 			return
 		}
 		
+		// Leave out drafts and blogs outside their publishing schedule
+		now := time.Now()
+		blogs := []models.Blog{}
+		for _, blog := range allBlogs {
+			if blog.IsLive(now) {
+				blogs = append(blogs, blog)
+			}
+		}
+		
 		// Convert blogs to JSON for embedding
 		blogData, err := json.Marshal(blogs)
 		if err != nil {
@@ -295,7 +504,7 @@ func setupSSRRoutes(router *mux.Router, blogStore models.BlogStore, templates *t
 	})
 	
 	// New blog page
-	router.HandleFunc("/blogs/new", func(w http.ResponseWriter, r *http.Request) {
+	router.Handle("/blogs/new", requireLogin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "GET" {
 			return
 		}
@@ -308,17 +517,47 @@ func setupSSRRoutes(router *mux.Router, blogStore models.BlogStore, templates *t
 			http.Error(w, "Failed to render template", http.StatusInternalServerError)
 			return
 		}
-	})
+	})))
 	
 	
-	// Individual blog post pages
+	// Individual blog post pages. Unpublished posts look missing unless the
+	// request has a preview token for them or comes from one of their editors.
 	router.HandleFunc("/blogs/{slug}", func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		slug := vars["slug"]
+		now := time.Now()
 		
 		blog, err := blogStore.GetBlogBySlug(slug)
+		private := false
+		if err == nil {
+			var allowed bool
+			if allowed, private = blogPageAccess(r, blog, authService, previewSigner, now); !allowed {
+				err = fmt.Errorf("blog not found")
+			}
+		}
 		if err != nil {
+			// Permanently redirect links that use one of the blog's former
+			// slugs, so inbound links and search rankings survive renames
+			if history, ok := blogStore.(models.SlugHistoryStore); ok {
+				renamedBlog, resolveErr := history.ResolveSlug(slug)
+				if resolveErr == nil && renamedBlog.Slug != slug {
+					// Don't reveal where an unpublished blog moved to
+					if allowed, _ := blogPageAccess(r, renamedBlog, authService, previewSigner, now); !allowed {
+						renamedBlog = nil
+					}
+				}
+				if renamedBlog != nil && renamedBlog.Slug != slug {
+					target := "/blogs/" + url.PathEscape(renamedBlog.Slug)
+					if r.URL.RawQuery != "" {
+						target += "?" + r.URL.RawQuery
+					}
+					http.Redirect(w, r, target, http.StatusMovedPermanently)
+					return
+				}
+			}
+			
 			// Render 404 page
+			w.WriteHeader(http.StatusNotFound)
 			err = templates.ExecuteTemplate(w, "notfound.html", map[string]interface{}{
 				"JSFile":  assetInfo.JSFile,
 				"CSSFile": assetInfo.CSSFile,
@@ -329,6 +568,14 @@ func setupSSRRoutes(router *mux.Router, blogStore models.BlogStore, templates *t
 			return
 		}
 		
+		// Pages of unpublished blogs must stay out of shared caches and search
+		// engines, and must not leak a preview token through the Referer header
+		if private {
+			w.Header().Set("Cache-Control", "private, no-store")
+			w.Header().Set("X-Robots-Tag", "noindex, nofollow")
+			w.Header().Set("Referrer-Policy", "no-referrer")
+		}
+		
 		// Convert blog to JSON for embedding
 		blogData, err := json.Marshal(blog)
 		if err != nil {
@@ -336,6 +583,28 @@ func setupSSRRoutes(router *mux.Router, blogStore models.BlogStore, templates *t
 			return
 		}
 		
+		// Render the post body so crawlers and readers without JavaScript
+		// see the content, not just an empty React root
+		contentHTML, err := contentCache.RenderBlog(blog)
+		if err != nil {
+			http.Error(w, "Failed to render blog content", http.StatusInternalServerError)
+			return
+		}
+		
+		// Readers with a preview link fetch the hero with its token; the
+		// srcset is left out rather than signing every variant
+		heroSrc, heroSrcset := blog.ImageSrc(), blog.ImageSrcset()
+		if token := r.URL.Query().Get("preview"); private && token != "" && blog.Image != "" {
+			heroSrc, heroSrcset = models.PreviewImageURL(blog.Slug, blog.Image, token), ""
+		}
+		
+		// The hero's placeholder preview is a data URI, which templates only
+		// take from trusted code
+		var heroPlaceholder template.URL
+		if placeholder := blog.ImagePlaceholder(); strings.HasPrefix(placeholder, "data:image/png;base64,") {
+			heroPlaceholder = template.URL(placeholder)
+		}
+		
 		// Get base URL
 		baseURL := "http://localhost:8080"
 		if r.Host != "" {
@@ -348,11 +617,17 @@ func setupSSRRoutes(router *mux.Router, blogStore models.BlogStore, templates *t
 		
 		// Render template with embedded data
 		err = templates.ExecuteTemplate(w, "blog.html", map[string]interface{}{
-			"Blog":     blog,
-			"BaseURL":  baseURL,
-			"BlogData": template.JS(blogData),
-			"JSFile":   assetInfo.JSFile,
-			"CSSFile":  assetInfo.CSSFile,
+			"Blog":            blog,
+			"BaseURL":         baseURL,
+			"BlogData":        template.JS(blogData),
+			"ContentHTML":     contentHTML,
+			"Hero":            blog.MediaForFile(blog.Image),
+			"HeroSrc":         heroSrc,
+			"HeroSrcset":      heroSrcset,
+			"HeroPlaceholder": heroPlaceholder,
+			"Private":         private,
+			"JSFile":          assetInfo.JSFile,
+			"CSSFile":         assetInfo.CSSFile,
 		})
 		if err != nil {
 			http.Error(w, "Failed to render template", http.StatusInternalServerError)
@@ -360,14 +635,117 @@ func setupSSRRoutes(router *mux.Router, blogStore models.BlogStore, templates *t
 		}
 	})
 	
+	// renderListPage renders the live blogs matching a tag or category,
+	// newest first, or the 404 page when there are none
+	renderListPage := func(w http.ResponseWriter, r *http.Request, heading string, path string, feedQuery url.Values, matches func(models.Blog) bool) {
+		blogs, err := blogStore.GetAllBlogs()
+		if err != nil {
+			http.Error(w, "Failed to fetch blogs", http.StatusInternalServerError)
+			return
+		}
+		
+		now := time.Now()
+		matching := []models.Blog{}
+		for _, blog := range blogs {
+			if blog.IsLive(now) && matches(blog) {
+				matching = append(matching, blog)
+			}
+		}
+		sort.SliceStable(matching, func(i, j int) bool {
+			return matching[i].Created.After(matching[j].Created)
+		})
+		
+		if len(matching) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			templates.ExecuteTemplate(w, "notfound.html", map[string]interface{}{
+				"JSFile":  assetInfo.JSFile,
+				"CSSFile": assetInfo.CSSFile,
+			})
+			return
+		}
+		
+		// Convert blogs to JSON for embedding
+		blogData, err := json.Marshal(matching)
+		if err != nil {
+			http.Error(w, "Failed to serialize blog data", http.StatusInternalServerError)
+			return
+		}
+		
+		// Determine base URL for canonical links
+		baseURL := "https://" + r.Host
+		if strings.Contains(r.Host, "localhost") {
+			baseURL = "http://" + r.Host
+		}
+		
+		err = templates.ExecuteTemplate(w, "taxonomy.html", map[string]interface{}{
+			"Title":       heading + " - Go + React Blog Platform",
+			"Heading":     heading,
+			"Description": fmt.Sprintf("%s: %d posts", heading, len(matching)),
+			"BaseURL":     baseURL,
+			"Path":        template.URL(path),
+			"FeedQuery":   template.URL(feedQuery.Encode()),
+			"Blogs":       matching,
+			"BlogData":    template.JS(blogData),
+			"JSFile":      assetInfo.JSFile,
+			"CSSFile":     assetInfo.CSSFile,
+		})
+		if err != nil {
+			http.Error(w, "Failed to render template", http.StatusInternalServerError)
+			return
+		}
+	}
+	
+	// Tag pages
+	router.HandleFunc("/tags/{tag}", func(w http.ResponseWriter, r *http.Request) {
+		tags := models.NormalizeTags([]string{mux.Vars(r)["tag"]})
+		if len(tags) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		tag := tags[0]
+		
+		renderListPage(w, r, "Posts tagged #"+tag, "/tags/"+url.PathEscape(tag), url.Values{"tag": {tag}}, func(blog models.Blog) bool {
+			return blog.HasTag(tag)
+		})
+	}).Methods("GET")
+	
+	// Category pages
+	router.HandleFunc("/categories/{category}", func(w http.ResponseWriter, r *http.Request) {
+		category := models.NormalizeCategory(mux.Vars(r)["category"])
+		if category == "" {
+			http.NotFound(w, r)
+			return
+		}
+		
+		renderListPage(w, r, "Posts in "+category, "/categories/"+url.PathEscape(strings.ToLower(category)), url.Values{"category": {category}}, func(blog models.Blog) bool {
+			return blog.InCategory(category)
+		})
+	}).Methods("GET")
+	
 	// Edit blog page
-	router.HandleFunc("/blogs/{slug}/edit", func(w http.ResponseWriter, r *http.Request) {
+	router.Handle("/blogs/{slug}/edit", requireLogin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		slug := vars["slug"]
 		
+		// Only users who can edit the blog may see it here, drafts included;
+		// everyone else gets the same 404 as for a missing blog
+		user, _ := auth.UserFromContext(r.Context())
 		blog, err := blogStore.GetBlogBySlug(slug)
-		if err != nil {
+		if err != nil || !user.CanEditBlog(blog) {
+			// Send edit links that use a former slug to the current one
+			if history, ok := blogStore.(models.SlugHistoryStore); ok {
+				if renamedBlog, resolveErr := history.ResolveSlug(slug); resolveErr == nil && renamedBlog.Slug != slug && user.CanEditBlog(renamedBlog) {
+					target := "/blogs/" + url.PathEscape(renamedBlog.Slug) + "/edit"
+					if r.URL.RawQuery != "" {
+						target += "?" + r.URL.RawQuery
+					}
+					http.Redirect(w, r, target, http.StatusMovedPermanently)
+					return
+				}
+			}
+			
 			// Render 404 page
+			w.WriteHeader(http.StatusNotFound)
 			err = templates.ExecuteTemplate(w, "notfound.html", map[string]interface{}{
 				"JSFile":  assetInfo.JSFile,
 				"CSSFile": assetInfo.CSSFile,
@@ -386,9 +764,11 @@ func setupSSRRoutes(router *mux.Router, blogStore models.BlogStore, templates *t
 		}
 		
 		// Render template with embedded data
+		// The editor sends the ETag back in If-Match when saving
 		err = templates.ExecuteTemplate(w, "edit.html", map[string]interface{}{
 			"Blog":     blog,
 			"BlogData": template.JS(blogData),
+			"ETag":     blog.ETag(),
 			"JSFile":   assetInfo.JSFile,
 			"CSSFile":  assetInfo.CSSFile,
 		})
@@ -396,12 +776,174 @@ func setupSSRRoutes(router *mux.Router, blogStore models.BlogStore, templates *t
 			http.Error(w, "Failed to render template", http.StatusInternalServerError)
 			return
 		}
+	})))
+}
+
+// blogPageAccess reports whether a request may see a blog's page at now,
+// and whether the page is private because the blog isn't live. Private pages
+// are shown with a valid preview token for the blog or to users who can edit
+// it.
+func blogPageAccess(r *http.Request, blog *models.Blog, authService *auth.Service, previewSigner *auth.PreviewSigner, now time.Time) (allowed bool, private bool) {
+	if blog.IsLive(now) {
+		return true, false
+	}
+	if token := r.URL.Query().Get("preview"); token != "" && previewSigner.Verify(token, blog.ID, now) == nil {
+		return true, true
+	}
+	if user, err := authService.UserFromRequest(r); err == nil && user.CanEditBlog(blog) {
+		return true, true
+	}
+	return false, true
+}
+
+// setupLoginRoutes configures the server-rendered login form. It works
+// without the React build so admins can always sign in.
+func setupLoginRoutes(router *mux.Router, authService *auth.Service, templates *template.Template) {
+	renderLogin := func(w http.ResponseWriter, status int, next string, username string, errorMessage string) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(status)
+		err := templates.ExecuteTemplate(w, "login.html", map[string]interface{}{
+			"Next":     next,
+			"Username": username,
+			"Error":    errorMessage,
+		})
+		if err != nil {
+			fmt.Printf("❌ Failed to render login page: %v\n", err)
+		}
+	}
+	
+	router.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		renderLogin(w, http.StatusOK, safeRedirectPath(r.URL.Query().Get("next")), "", "")
+	}).Methods("GET")
+	
+	router.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		next := safeRedirectPath(r.FormValue("next"))
+		username := r.FormValue("username")
+		
+		token, session, _, err := authService.Login(username, r.FormValue("password"))
+		if err != nil {
+			if err.Error() == "invalid credentials" {
+				renderLogin(w, http.StatusUnauthorized, next, username, "Invalid username or password")
+			} else {
+				fmt.Printf("❌ Login failed: %v\n", err)
+				renderLogin(w, http.StatusInternalServerError, next, username, "Something went wrong, please try again")
+			}
+			return
+		}
+		
+		auth.SetSessionCookie(w, r, token, session.Expires)
+		http.Redirect(w, r, next, http.StatusSeeOther)
+	}).Methods("POST")
+}
+
+// safeRedirectPath returns next if it is a path on this site, or "/" so the
+// login form can't be used to send users to another host
+func safeRedirectPath(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
+
+// runCreateAdmin implements the "create-admin" command, which adds an admin
+// account. The password comes from -password, BLOG_ADMIN_PASSWORD or stdin.
+func runCreateAdmin(authService *auth.Service, args []string) {
+	flags := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	username := flags.String("username", "admin", "username of the new admin")
+	password := flags.String("password", os.Getenv("BLOG_ADMIN_PASSWORD"), "password of the new admin (default $BLOG_ADMIN_PASSWORD, or read from stdin)")
+	flags.Parse(args)
+	
+	if *password == "" {
+		fmt.Printf("Password for %s: ", *username)
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			log.Fatalf("Failed to read password: %v", err)
+		}
+		*password = strings.TrimRight(line, "\r\n")
+	}
+	
+	user, err := authService.CreateUser(*username, *password, models.RoleAdmin)
+	if err != nil {
+		log.Fatalf("Failed to create admin: %v", err)
+	}
+	
+	fmt.Printf("✅ Created admin user %s (%s)\n", user.Username, user.ID)
+}
+
+// setupSearchRoutes configures the server-rendered search page. Like the
+// login form it is a plain HTML page, so it works without the React app.
+func setupSearchRoutes(router *mux.Router, searchIndex *search.Index, templates *template.Template) {
+	// Pages past maxPage fall back to the first, like malformed ones, so the
+	// offset can't overflow
+	const resultsPerPage = 10
+	const maxPage = 10000
+	
+	router.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		query := strings.TrimSpace(r.URL.Query().Get("q"))
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil || page < 1 || page > maxPage {
+			page = 1
+		}
+		
+		type searchResult struct {
+			Blog    models.Blog
+			Snippet template.HTML
+		}
+		results := []searchResult{}
+		total := 0
+		if query != "" {
+			var found []search.Result
+			found, total = searchIndex.Search(query, search.Options{
+				Offset: (page - 1) * resultsPerPage,
+				Limit:  resultsPerPage,
+			})
+			for _, result := range found {
+				// Snippets are escaped by the search package except for <mark>
+				results = append(results, searchResult{Blog: result.Blog, Snippet: template.HTML(result.Snippet)})
+			}
+		}
+		
+		pageURL := func(page int) string {
+			return "/search?" + url.Values{"q": {query}, "page": {strconv.Itoa(page)}}.Encode()
+		}
+		var prevURL, nextURL string
+		if page > 1 {
+			prevURL = pageURL(page - 1)
+		}
+		if page*resultsPerPage < total {
+			nextURL = pageURL(page + 1)
+		}
+		
+		err = templates.ExecuteTemplate(w, "search.html", map[string]interface{}{
+			"Query":   query,
+			"Results": results,
+			"Total":   total,
+			"PrevURL": prevURL,
+			"NextURL": nextURL,
+		})
+		if err != nil {
+			http.Error(w, "Failed to render template", http.StatusInternalServerError)
+		}
+	}).Methods("GET")
+}
+
+// setupFeedRoutes registers the RSS, Atom and JSON feeds of published blogs
+func setupFeedRoutes(router *mux.Router, blogStore models.BlogStore) {
+	feedHandler := feeds.NewHandler(blogStore, feeds.Config{
+		Title:       "Go + React Blog Platform",
+		Description: "A modern blog platform built with Go and React",
 	})
+
+	router.HandleFunc("/feed.xml", feedHandler.RSS).Methods("GET", "HEAD")
+	router.HandleFunc("/atom.xml", feedHandler.Atom).Methods("GET", "HEAD")
+	router.HandleFunc("/feed.json", feedHandler.JSON).Methods("GET", "HEAD")
 }
 
-// generateSitemapXML generates a sitemap XML from blog data
+// generateSitemapXML generates a sitemap XML from blog data, listing only
+// blogs that are live
 func generateSitemapXML(blogs []models.Blog, host string) string {
-	currentDate := time.Now().Format("2006-01-02")
+	now := time.Now()
+	currentDate := now.Format("2006-01-02")
 	baseURL := "http://" + host
 	if strings.Contains(host, "localhost") {
 		baseURL = "http://" + host
@@ -421,7 +963,7 @@ func generateSitemapXML(blogs []models.Blog, host string) string {
 
 	// Add individual blog posts
 	for _, blog := range blogs {
-		if blog.Published {
+		if blog.IsLive(now) {
 			lastmod := blog.Updated.Format("2006-01-02")
 			xml += `
   <url>
@@ -433,8 +975,51 @@ func generateSitemapXML(blogs []models.Blog, host string) string {
 		}
 	}
 
+	// Add tag and category pages, dated by their most recently updated post
+	tagUpdated := make(map[string]time.Time)
+	categoryUpdated := make(map[string]time.Time)
+	for _, blog := range blogs {
+		if !blog.IsLive(now) {
+			continue
+		}
+		for _, tag := range blog.Tags {
+			if blog.Updated.After(tagUpdated[tag]) {
+				tagUpdated[tag] = blog.Updated
+			}
+		}
+		// Categories match case-insensitively, so list each one once
+		category := strings.ToLower(blog.Category)
+		if category != "" && blog.Updated.After(categoryUpdated[category]) {
+			categoryUpdated[category] = blog.Updated
+		}
+	}
+	xml += sitemapTaxonomyURLs(baseURL+"/tags/", tagUpdated)
+	xml += sitemapTaxonomyURLs(baseURL+"/categories/", categoryUpdated)
+
 	xml += `
 </urlset>`
 
 	return xml
 }
+
+// sitemapTaxonomyURLs renders sitemap entries for tag or category pages,
+// sorted by name
+func sitemapTaxonomyURLs(prefix string, lastUpdated map[string]time.Time) string {
+	names := make([]string, 0, len(lastUpdated))
+	for name := range lastUpdated {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var xml string
+	for _, name := range names {
+		xml += `
+  <url>
+    <loc>` + template.HTMLEscapeString(prefix+url.PathEscape(name)) + `</loc>
+    <lastmod>` + lastUpdated[name].Format("2006-01-02") + `</lastmod>
+    <changefreq>weekly</changefreq>
+    <priority>0.5</priority>
+  </url>`
+	}
+	return xml
+}
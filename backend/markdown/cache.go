@@ -0,0 +1,65 @@
+package markdown
+
+import (
+	"crypto/sha256"
+	"html/template"
+	"sync"
+
+	"go-react-backend/models"
+
+	"github.com/google/uuid"
+)
+
+// cacheEntry is the rendered content of a blog, with the hash of the source
+// it was rendered from
+type cacheEntry struct {
+	source [sha256.Size]byte
+	html   template.HTML
+}
+
+// Cache keeps the rendered content of each blog, rendering it again only
+// when the content changes. Entries are checked against the content itself
+// rather than the Updated timestamp, which edits made directly to
+// content.md leave alone.
+type Cache struct {
+	renderer *Renderer
+	mu       sync.RWMutex
+	entries  map[uuid.UUID]cacheEntry
+}
+
+// NewCache creates a rendering cache backed by renderer
+func NewCache(renderer *Renderer) *Cache {
+	return &Cache{
+		renderer: renderer,
+		entries:  make(map[uuid.UUID]cacheEntry),
+	}
+}
+
+// RenderBlog returns the blog's content as sanitized HTML
+func (c *Cache) RenderBlog(blog *models.Blog) (template.HTML, error) {
+	source := sha256.Sum256([]byte(blog.Content))
+	c.mu.RLock()
+	entry, ok := c.entries[blog.ID]
+	c.mu.RUnlock()
+	if ok && entry.source == source {
+		return entry.html, nil
+	}
+
+	rendered, err := c.renderer.Render(blog.Content)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.entries[blog.ID] = cacheEntry{source: source, html: rendered}
+	c.mu.Unlock()
+
+	return rendered, nil
+}
+
+// Forget drops the cached content of a blog, e.g. after it was deleted
+func (c *Cache) Forget(id uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, id)
+}
@@ -0,0 +1,34 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-react-backend/models"
+
+	"github.com/google/uuid"
+)
+
+func TestCacheRendersChangedContent(t *testing.T) {
+	cache := NewCache(NewRenderer())
+	blog := &models.Blog{ID: uuid.New(), Content: "first version", Updated: time.Now()}
+
+	html, err := cache.RenderBlog(blog)
+	if err != nil {
+		t.Fatalf("RenderBlog: %v", err)
+	}
+	if !strings.Contains(string(html), "first version") {
+		t.Fatalf("RenderBlog = %q, want the first version", html)
+	}
+
+	// content.md edited on disk: same Updated time, new content
+	blog.Content = "second version"
+	html, err = cache.RenderBlog(blog)
+	if err != nil {
+		t.Fatalf("RenderBlog: %v", err)
+	}
+	if !strings.Contains(string(html), "second version") {
+		t.Fatalf("RenderBlog = %q, want the second version", html)
+	}
+}
@@ -0,0 +1,49 @@
+package markdown
+
+import (
+	"bytes"
+	"html/template"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// Renderer converts CommonMark with GitHub Flavored Markdown extensions
+// (tables, strikethrough, task lists, autolinks) into sanitized HTML
+type Renderer struct {
+	markdown goldmark.Markdown
+	policy   *bluemonday.Policy
+}
+
+// NewRenderer creates a markdown renderer
+func NewRenderer() *Renderer {
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		// Raw HTML is passed through here and cleaned up by the policy below
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+
+	// UGCPolicy keeps formatting, links, images, tables and fenced code
+	// language classes while dropping scripts, styles and event handlers
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("id").Matching(regexp.MustCompile(`^[\w-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	policy.AllowAttrs("style").Matching(regexp.MustCompile(`^text-align:(left|center|right)$`)).OnElements("th", "td")
+	policy.AllowAttrs("type", "checked", "disabled").OnElements("input")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+
+	return &Renderer{markdown: md, policy: policy}
+}
+
+// Render converts markdown source into HTML that is safe to embed in a page
+func (r *Renderer) Render(source string) (template.HTML, error) {
+	var buf bytes.Buffer
+	if err := r.markdown.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return template.HTML(r.policy.SanitizeBytes(buf.Bytes())), nil
+}
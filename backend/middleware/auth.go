@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go-react-backend/auth"
+	"go-react-backend/models"
+)
+
+// sendAuthError responds to a failed credential check
+func sendAuthError(w http.ResponseWriter, err error) {
+	if err.Error() == "unauthenticated" {
+		models.SendError(w, http.StatusUnauthorized, "Authentication required", "Log in or send a valid API token to perform this action")
+	} else {
+		models.SendError(w, http.StatusInternalServerError, "Failed to check credentials", err.Error())
+	}
+}
+
+// RequireAuth rejects API requests without a valid session cookie or
+// "Authorization: Bearer" API token with 401, and passes the user (and
+// token, if any) on in the request context
+func RequireAuth(authService *auth.Service) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if header := r.Header.Get("Authorization"); header != "" {
+				bearer, ok := strings.CutPrefix(header, "Bearer ")
+				if !ok {
+					models.SendError(w, http.StatusUnauthorized, "Authentication required", "Authorization header must use the Bearer scheme")
+					return
+				}
+
+				user, token, err := authService.AuthenticateAPIToken(strings.TrimSpace(bearer))
+				if err != nil {
+					sendAuthError(w, err)
+					return
+				}
+
+				ctx := auth.WithAPIToken(auth.WithUser(r.Context(), user), token)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
+			user, err := authService.UserFromRequest(r)
+			if err != nil {
+				sendAuthError(w, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithUser(r.Context(), user)))
+		})
+	}
+}
+
+// OptionalAuth passes the user (and token, if any) on in the request context
+// when the request carries valid credentials, and lets anonymous requests
+// through, for routes whose response depends on who is asking. A request
+// with a bad API token is still rejected with 401.
+func OptionalAuth(authService *auth.Service) func(http.Handler) http.Handler {
+	requireAuth := RequireAuth(authService)
+	return func(next http.Handler) http.Handler {
+		authenticated := requireAuth(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "" {
+				authenticated.ServeHTTP(w, r)
+				return
+			}
+
+			// A missing or expired session cookie just means anonymous
+			if user, err := authService.UserFromRequest(r); err == nil {
+				r = r.WithContext(auth.WithUser(r.Context(), user))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireSession only lets through requests authenticated with a browser
+// session, for actions API tokens must not perform (e.g. minting tokens).
+// It must run after RequireAuth.
+func RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := auth.APITokenFromContext(r.Context()); ok {
+			models.SendError(w, http.StatusForbidden, "Session required", "API tokens cannot perform this action")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireScope rejects requests whose credentials don't grant scope with 403.
+// It must run after RequireAuth.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !auth.HasScope(r.Context(), scope) {
+				models.SendError(w, http.StatusForbidden, "Insufficient scope", "This action requires the "+scope+" scope")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequirePermission rejects requests from users whose role lacks permission
+// with 403. It must run after RequireAuth.
+func RequirePermission(permission models.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := auth.UserFromContext(r.Context())
+			if !ok || !user.Can(permission) {
+				models.SendError(w, http.StatusForbidden, "Forbidden", "Your role does not allow this action")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireFileScope is RequireScope for multipart requests that upload a file
+// in field; requests without such a file pass through unchecked
+func RequireFileScope(field string, scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		requireScope := RequireScope(scope)(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Parse with the handlers' limit; the parsed form is reused by them
+			if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+				if err := r.ParseMultipartForm(10 << 20); err == nil && len(r.MultipartForm.File[field]) > 0 {
+					requireScope.ServeHTTP(w, r)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireAuthPage redirects browsers without a valid session to the login
+// page, which sends them back here after logging in
+func RequireAuthPage(authService *auth.Service) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, err := authService.UserFromRequest(r)
+			if err != nil {
+				http.Redirect(w, r, "/login?next="+url.QueryEscape(r.URL.RequestURI()), http.StatusSeeOther)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithUser(r.Context(), user)))
+		})
+	}
+}
@@ -2,16 +2,31 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/rs/cors"
 )
 
-// SetupCORS configures CORS middleware
-func SetupCORS() func(http.Handler) http.Handler {
+// SetupCORS configures CORS middleware. Credentialed cross-origin requests
+// are only accepted from allowedOrigins.
+func SetupCORS(allowedOrigins []string) func(http.Handler) http.Handler {
+	origins := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins[strings.ToLower(origin)] = true
+		}
+	}
+	
 	c := cors.New(cors.Options{
-		AllowedOrigins:   []string{"*"}, // Allow all origins in production
+		// Matching origins ourselves also keeps an empty list from falling
+		// back to cors' default of allowing every origin
+		AllowOriginFunc: func(origin string) bool {
+			return origins[strings.ToLower(origin)]
+		},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"*"},
+		// Clients need the ETag to send it back in If-Match
+		ExposedHeaders:   []string{"ETag"},
 		AllowCredentials: true,
 	})
 	
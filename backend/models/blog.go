@@ -1,97 +1,173 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrSlugExists is returned by every blog store when a blog is created,
+// renamed or restored under a slug another blog already has
+var ErrSlugExists = errors.New("slug already exists")
+
 // BlogStore represents the storage interface for blogs
 type BlogStore interface {
 	GetAllBlogs() ([]Blog, error)
 	GetBlogBySlug(slug string) (*Blog, error)
+	GetBlogByID(id uuid.UUID) (*Blog, error)
 	CreateBlog(blog Blog) (Blog, error)
 	UpdateBlogBySlug(slug string, updates UpdateBlogRequest) (*Blog, error)
 	DeleteBlogBySlug(slug string) error
 	SaveBlogImage(slug string, imageFilename string, imageData []byte) error
+	GetBlogImage(slug string, imageFilename string) ([]byte, error)
 }
 
 // Blog represents a blog post in the system
 type Blog struct {
-	ID              uuid.UUID `json:"id"`
-	Title           string    `json:"title"`
-	Content         string    `json:"content"`
-	Image           string    `json:"image"` // Image filename
-	AuthorName      string    `json:"author_name"`
-	AuthorUsername  string    `json:"author_username"`
-	MetaName        string    `json:"meta_name"`
-	MetaDescription string    `json:"meta_description"`
-	Slug            string    `json:"slug"`
-	Created         time.Time `json:"created"`
-	Updated         time.Time `json:"updated"`
-	Published       bool      `json:"published"`
+	ID              uuid.UUID      `json:"id"`
+	Title           string         `json:"title"`
+	Content         string         `json:"content"`
+	Image           string         `json:"image"`           // Image filename
+	ImageVariants   []ImageVariant `json:"image_variants"`  // Resized copies of the image, narrowest first
+	ImageThumbnail  string         `json:"image_thumbnail"` // Thumbnail filename
+	ImageMimeType   string         `json:"image_mime_type"` // MIME type of the image, its variants and thumbnail
+	Media           []MediaItem    `json:"media"`           // Images for the content; the hero image is one of them
+	AuthorName      string         `json:"author_name"`
+	AuthorUsername  string         `json:"author_username"`
+	MetaName        string         `json:"meta_name"`
+	MetaDescription string         `json:"meta_description"`
+	Tags            []string       `json:"tags"`
+	Category        string         `json:"category"`
+	Slug            string         `json:"slug"`
+	Created         time.Time      `json:"created"`
+	Updated         time.Time      `json:"updated"`
+	Published       bool           `json:"published"`
+	PublishAt       *time.Time     `json:"publish_at"`   // Publish automatically at this time, if set
+	UnpublishAt     *time.Time     `json:"unpublish_at"` // Unpublish automatically at this time, if set
 }
 
 // CreateBlogRequest represents the data needed to create a blog
 type CreateBlogRequest struct {
-	Title           string `json:"title" validate:"required"`
-	Content         string `json:"content" validate:"required"`
-	Image           string `json:"image"` // Image filename
-	AuthorName      string `json:"author_name"`
-	AuthorUsername  string `json:"author_username"`
-	MetaName        string `json:"meta_name"`
-	MetaDescription string `json:"meta_description"`
-	Slug            string `json:"slug"`
-	Published       bool   `json:"published"`
+	Title           string     `json:"title" validate:"required"`
+	Content         string     `json:"content" validate:"required"`
+	Image           string     `json:"image"` // Image filename
+	AuthorName      string     `json:"author_name"`
+	AuthorUsername  string     `json:"author_username"`
+	MetaName        string     `json:"meta_name"`
+	MetaDescription string     `json:"meta_description"`
+	Tags            []string   `json:"tags"`
+	Category        string     `json:"category"`
+	Slug            string     `json:"slug"`
+	Published       bool       `json:"published"`
+	PublishAt       *time.Time `json:"publish_at"`
+	UnpublishAt     *time.Time `json:"unpublish_at"`
 }
 
 // UpdateBlogRequest represents the data needed to update a blog
 type UpdateBlogRequest struct {
-	Title           *string `json:"title,omitempty"`
-	Content         *string `json:"content,omitempty"`
-	Image           *string `json:"image,omitempty"` // Image filename
-	AuthorName      *string `json:"author_name,omitempty"`
-	AuthorUsername  *string `json:"author_username,omitempty"`
-	MetaName        *string `json:"meta_name,omitempty"`
-	MetaDescription *string `json:"meta_description,omitempty"`
-	Slug            *string `json:"slug,omitempty"`
-	Published       *bool   `json:"published,omitempty"`
+	Title           *string         `json:"title,omitempty"`
+	Content         *string         `json:"content,omitempty"`
+	Image           *string         `json:"image,omitempty"`           // Image filename
+	ImageVariants   *[]ImageVariant `json:"image_variants,omitempty"`  // Set along with Image
+	ImageThumbnail  *string         `json:"image_thumbnail,omitempty"` // Set along with Image
+	ImageMimeType   *string         `json:"image_mime_type,omitempty"` // Set along with Image
+	Media           *[]MediaItem    `json:"media,omitempty"`
+	AuthorName      *string         `json:"author_name,omitempty"`
+	AuthorUsername  *string         `json:"author_username,omitempty"`
+	MetaName        *string         `json:"meta_name,omitempty"`
+	MetaDescription *string         `json:"meta_description,omitempty"`
+	Tags            *[]string       `json:"tags,omitempty"`
+	Category        *string         `json:"category,omitempty"`
+	Slug            *string         `json:"slug,omitempty"`
+	Published       *bool           `json:"published,omitempty"`
+	PublishAt       *time.Time      `json:"publish_at,omitempty"`   // The zero time clears the schedule
+	UnpublishAt     *time.Time      `json:"unpublish_at,omitempty"` // The zero time clears the schedule
 }
 
 // BlogResponse represents the blog data sent to clients
 type BlogResponse struct {
-	ID              string `json:"id"`
-	Title           string `json:"title"`
-	Content         string `json:"content"`
-	Image           string `json:"image"` // Image filename
-	AuthorName      string `json:"author_name"`
-	AuthorUsername  string `json:"author_username"`
-	MetaName        string `json:"meta_name"`
-	MetaDescription string `json:"meta_description"`
-	Slug            string `json:"slug"`
-	Created         string `json:"created"`
-	Updated         string `json:"updated"`
-	Published       bool   `json:"published"`
+	ID               string              `json:"id"`
+	Title            string              `json:"title"`
+	Content          string              `json:"content"`
+	Image            string              `json:"image"` // Image filename
+	ImageVariants    []ImageVariant      `json:"image_variants"`
+	ImageThumbnail   string              `json:"image_thumbnail"`
+	ImageMimeType    string              `json:"image_mime_type"`
+	ImagePlaceholder string              `json:"image_placeholder"` // Tiny preview of the hero image as a data URI
+	ImageColor       string              `json:"image_color"`       // Dominant color of the hero image, #rrggbb
+	Media            []MediaItemResponse `json:"media"`
+	AuthorName       string              `json:"author_name"`
+	AuthorUsername   string              `json:"author_username"`
+	MetaName         string              `json:"meta_name"`
+	MetaDescription  string              `json:"meta_description"`
+	Tags             []string            `json:"tags"`
+	Category         string              `json:"category"`
+	Slug             string              `json:"slug"`
+	Created          string              `json:"created"`
+	Updated          string              `json:"updated"`
+	Published        bool                `json:"published"`
+	PublishAt        *string             `json:"publish_at"`
+	UnpublishAt      *string             `json:"unpublish_at"`
+}
+
+// BlogListResponse represents one page of a blog listing sent to clients
+type BlogListResponse struct {
+	Blogs      []BlogResponse `json:"blogs"`
+	Page       int            `json:"page"`
+	PerPage    int            `json:"per_page"`
+	Total      int            `json:"total"`
+	TotalPages int            `json:"total_pages"`
 }
 
 // Convert Blog to BlogResponse
 func (b *Blog) ToResponse() BlogResponse {
+	tags := b.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	variants := b.ImageVariants
+	if variants == nil {
+		variants = []ImageVariant{}
+	}
+
 	return BlogResponse{
-		ID:              b.ID.String(),
-		Title:           b.Title,
-		Content:         b.Content,
-		Image:           b.Image,
-		AuthorName:      b.AuthorName,
-		AuthorUsername:  b.AuthorUsername,
-		MetaName:        b.MetaName,
-		MetaDescription: b.MetaDescription,
-		Slug:            b.Slug,
-		Created:         b.Created.Format(time.RFC3339),
-		Updated:         b.Updated.Format(time.RFC3339),
-		Published:       b.Published,
+		ID:               b.ID.String(),
+		Title:            b.Title,
+		Content:          b.Content,
+		Image:            b.Image,
+		ImageVariants:    variants,
+		ImageThumbnail:   b.ImageThumbnail,
+		ImageMimeType:    b.ImageMimeType,
+		ImagePlaceholder: b.ImagePlaceholder(),
+		ImageColor:       b.ImageColor(),
+		Media:            b.MediaResponses(),
+		AuthorName:       b.AuthorName,
+		AuthorUsername:   b.AuthorUsername,
+		MetaName:         b.MetaName,
+		MetaDescription:  b.MetaDescription,
+		Tags:             tags,
+		Category:         b.Category,
+		Slug:             b.Slug,
+		Created:          b.Created.Format(time.RFC3339),
+		Updated:          b.Updated.Format(time.RFC3339),
+		Published:        b.Published,
+		PublishAt:        formatSchedule(b.PublishAt),
+		UnpublishAt:      formatSchedule(b.UnpublishAt),
 	}
 }
 
+// ETag returns a strong entity tag for this version of the blog, derived from
+// the representation clients see, for If-Match and If-None-Match checks
+func (b *Blog) ETag() string {
+	data, _ := json.Marshal(b.ToResponse())
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
 // ValidateCreateRequest validates a create blog request
 func (req *CreateBlogRequest) Validate() error {
 	if req.Title == "" {
@@ -100,7 +176,24 @@ func (req *CreateBlogRequest) Validate() error {
 	if req.Content == "" {
 		return &ValidationError{Field: "content", Message: "Content is required"}
 	}
-	return nil
+	if err := ValidateTaxonomy(req.Tags, req.Category); err != nil {
+		return err
+	}
+	return ValidateSchedule(req.PublishAt, req.UnpublishAt)
+}
+
+// Validate validates an update blog request. Schedules are checked against
+// the blog they apply to by ValidateSchedule.
+func (req *UpdateBlogRequest) Validate() error {
+	var tags []string
+	if req.Tags != nil {
+		tags = *req.Tags
+	}
+	var category string
+	if req.Category != nil {
+		category = *req.Category
+	}
+	return ValidateTaxonomy(tags, category)
 }
 
 // ValidationError represents a validation error
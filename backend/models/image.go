@@ -0,0 +1,98 @@
+package models
+
+import (
+	"net/url"
+)
+
+// ImageVariant is a resized copy of a blog's image, stored next to it so
+// pages can let browsers pick a size with srcset
+type ImageVariant struct {
+	Filename string `json:"filename"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+}
+
+// ImageFiles returns the filenames of every image the blog stores: the files
+// of each media item, including the hero image's
+func (b *Blog) ImageFiles() []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, item := range b.MediaItems() {
+		for _, file := range item.Files() {
+			if !seen[file] {
+				seen[file] = true
+				files = append(files, file)
+			}
+		}
+	}
+	return files
+}
+
+// HasImageFile reports whether filename is one of the blog's image files
+func (b *Blog) HasImageFile(filename string) bool {
+	for _, file := range b.ImageFiles() {
+		if file == filename {
+			return true
+		}
+	}
+	return false
+}
+
+// ImageForWidth returns the filename of the hero image's narrowest variant at
+// least width pixels wide, as MediaItem.FileForWidth does
+func (b *Blog) ImageForWidth(width int) string {
+	hero := b.heroFromFields()
+	return hero.FileForWidth(width)
+}
+
+// ImageSrc returns the URL of the blog's image, or "" if it has none
+func (b *Blog) ImageSrc() string {
+	if b.Image == "" {
+		return ""
+	}
+	return ImageURL(b.Slug, b.Image)
+}
+
+// ThumbnailSrc returns the URL of the blog's thumbnail, or "" if it has none
+func (b *Blog) ThumbnailSrc() string {
+	if b.ImageThumbnail == "" {
+		return ""
+	}
+	return ImageURL(b.Slug, b.ImageThumbnail)
+}
+
+// ImagePlaceholder returns the tiny preview of the hero image as a data URI,
+// or "" if it has none
+func (b *Blog) ImagePlaceholder() string {
+	if hero := b.findMedia(b.Image); hero != nil {
+		return hero.Placeholder
+	}
+	return ""
+}
+
+// ImageColor returns the dominant color of the hero image as #rrggbb, or ""
+// if it has none
+func (b *Blog) ImageColor() string {
+	if hero := b.findMedia(b.Image); hero != nil {
+		return hero.Color
+	}
+	return ""
+}
+
+// ImageSrcset returns the value of a srcset attribute listing the hero
+// image's variants by width, or "" if it has none
+func (b *Blog) ImageSrcset() string {
+	hero := b.heroFromFields()
+	return hero.Srcset(b.Slug)
+}
+
+// ImageURL returns the path an image file of the blog with slug is served at
+func ImageURL(slug string, filename string) string {
+	return "/api/images/" + url.PathEscape(slug) + "/" + url.PathEscape(filename)
+}
+
+// PreviewImageURL returns ImageURL with a preview token, which lets anyone
+// holding a preview link for an unpublished blog fetch its images
+func PreviewImageURL(slug string, filename string, token string) string {
+	return ImageURL(slug, filename) + "?preview=" + url.QueryEscape(token)
+}
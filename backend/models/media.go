@@ -0,0 +1,232 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Limits on a blog's media collection
+const (
+	MaxMediaItems    = 100
+	MaxAltTextLength = 300
+	MaxCaptionLength = 1000
+)
+
+// MediaItem is an image in a blog's media collection, either embedded in its
+// content or used as its hero image (see Blog.Image). Its files are named
+// for the blog; those of items with a Hash are stored in the media library.
+type MediaItem struct {
+	Hash      string         `json:"hash"` // Library asset holding the files, or "" if stored with the blog
+	Filename  string         `json:"filename"`
+	MimeType  string         `json:"mime_type"`
+	Width     int            `json:"width"`
+	Height    int            `json:"height"`
+	Variants  []ImageVariant `json:"variants"` // Narrowest first
+	Thumbnail string         `json:"thumbnail"`
+	AltText   string         `json:"alt_text"`
+	Caption   string         `json:"caption"`
+	Created   time.Time      `json:"created"`
+
+	Placeholder string `json:"placeholder"` // Tiny PNG preview as a data URI, shown while the image loads
+	Color       string `json:"color"`       // Dominant color as #rrggbb
+}
+
+// UpdateMediaRequest represents the data needed to update a media item
+type UpdateMediaRequest struct {
+	AltText *string `json:"alt_text,omitempty"`
+	Caption *string `json:"caption,omitempty"`
+	Hero    *bool   `json:"hero,omitempty"` // Make the item the hero image, or stop it being one
+}
+
+// MediaItemResponse represents a media item sent to clients
+type MediaItemResponse struct {
+	Hash        string         `json:"hash"`
+	Filename    string         `json:"filename"`
+	URL         string         `json:"url"`
+	MimeType    string         `json:"mime_type"`
+	Width       int            `json:"width"`
+	Height      int            `json:"height"`
+	Variants    []ImageVariant `json:"variants"`
+	Srcset      string         `json:"srcset"`
+	Thumbnail   string         `json:"thumbnail"`
+	Placeholder string         `json:"placeholder"`
+	Color       string         `json:"color"`
+	AltText     string         `json:"alt_text"`
+	Caption     string         `json:"caption"`
+	Hero        bool           `json:"hero"`
+	Created     string         `json:"created"`
+}
+
+// Files returns the filenames of the item's image, variants and thumbnail
+func (m *MediaItem) Files() []string {
+	files := []string{m.Filename}
+	for _, variant := range m.Variants {
+		files = append(files, variant.Filename)
+	}
+	if m.Thumbnail != "" {
+		files = append(files, m.Thumbnail)
+	}
+	return files
+}
+
+// FileForWidth returns the filename of the narrowest variant at least width
+// pixels wide, or of the widest variant if none is. Items without variants
+// get the image itself.
+func (m *MediaItem) FileForWidth(width int) string {
+	var best *ImageVariant
+	for i := range m.Variants {
+		variant := &m.Variants[i]
+		switch {
+		case best == nil:
+			best = variant
+		case best.Width < width:
+			// Anything wider is closer to what was asked for
+			if variant.Width > best.Width {
+				best = variant
+			}
+		case variant.Width >= width && variant.Width < best.Width:
+			best = variant
+		}
+	}
+
+	if best == nil {
+		return m.Filename
+	}
+	return best.Filename
+}
+
+// Srcset returns the value of a srcset attribute listing the item's variants
+// by width, for the blog with slug, or "" if it has none
+func (m *MediaItem) Srcset(slug string) string {
+	candidates := make([]string, 0, len(m.Variants))
+	for _, variant := range m.Variants {
+		candidates = append(candidates, fmt.Sprintf("%s %dw", ImageURL(slug, variant.Filename), variant.Width))
+	}
+	return strings.Join(candidates, ", ")
+}
+
+// ToResponse converts the item of the blog with slug for clients
+func (m *MediaItem) ToResponse(slug string, hero bool) MediaItemResponse {
+	variants := m.Variants
+	if variants == nil {
+		variants = []ImageVariant{}
+	}
+
+	return MediaItemResponse{
+		Hash:        m.Hash,
+		Filename:    m.Filename,
+		URL:         ImageURL(slug, m.Filename),
+		MimeType:    m.MimeType,
+		Width:       m.Width,
+		Height:      m.Height,
+		Variants:    variants,
+		Srcset:      m.Srcset(slug),
+		Thumbnail:   m.Thumbnail,
+		Placeholder: m.Placeholder,
+		Color:       m.Color,
+		AltText:     m.AltText,
+		Caption:     m.Caption,
+		Hero:        hero,
+		Created:     m.Created.Format(time.RFC3339),
+	}
+}
+
+// MediaItems returns the blog's media collection. A hero image uploaded
+// before collections existed is listed as an item of its own.
+func (b *Blog) MediaItems() []MediaItem {
+	items := append([]MediaItem(nil), b.Media...)
+	if b.Image != "" && b.findMedia(b.Image) == nil {
+		items = append([]MediaItem{b.heroFromFields()}, items...)
+	}
+	return items
+}
+
+// MediaForFile returns the media item that filename (its image, one of its
+// variants or its thumbnail) belongs to, or nil if there is none
+func (b *Blog) MediaForFile(filename string) *MediaItem {
+	items := b.MediaItems()
+	for i := range items {
+		for _, file := range items[i].Files() {
+			if file == filename {
+				return &items[i]
+			}
+		}
+	}
+	return nil
+}
+
+// MediaResponses converts the blog's media collection for clients
+func (b *Blog) MediaResponses() []MediaItemResponse {
+	items := b.MediaItems()
+	responses := make([]MediaItemResponse, 0, len(items))
+	for i := range items {
+		responses = append(responses, items[i].ToResponse(b.Slug, items[i].Filename == b.Image))
+	}
+	return responses
+}
+
+// ImageAlt returns the alt text of the blog's hero image, falling back to
+// the blog's title
+func (b *Blog) ImageAlt() string {
+	if hero := b.findMedia(b.Image); hero != nil && hero.AltText != "" {
+		return hero.AltText
+	}
+	return b.Title
+}
+
+// SetHeroImage makes item the blog's hero image, or clears the hero image
+// when item is nil. item should be in the blog's media collection.
+func (b *Blog) SetHeroImage(item *MediaItem) {
+	if item == nil {
+		item = &MediaItem{}
+	}
+	b.Image = item.Filename
+	b.ImageVariants = item.Variants
+	b.ImageThumbnail = item.Thumbnail
+	b.ImageMimeType = item.MimeType
+}
+
+// SetHeroImage makes the update set the blog's hero image to item, or clear
+// it when item is nil
+func (req *UpdateBlogRequest) SetHeroImage(item *MediaItem) {
+	var hero Blog
+	hero.SetHeroImage(item)
+	req.Image = &hero.Image
+	req.ImageVariants = &hero.ImageVariants
+	req.ImageThumbnail = &hero.ImageThumbnail
+	req.ImageMimeType = &hero.ImageMimeType
+}
+
+// ValidateMediaText checks a media item's alt text and caption
+func ValidateMediaText(altText string, caption string) error {
+	if len(altText) > MaxAltTextLength {
+		return &ValidationError{Field: "alt_text", Message: fmt.Sprintf("Alt text must be at most %d characters", MaxAltTextLength)}
+	}
+	if len(caption) > MaxCaptionLength {
+		return &ValidationError{Field: "caption", Message: fmt.Sprintf("Caption must be at most %d characters", MaxCaptionLength)}
+	}
+	return nil
+}
+
+// findMedia returns the stored media item named filename, or nil
+func (b *Blog) findMedia(filename string) *MediaItem {
+	for i := range b.Media {
+		if b.Media[i].Filename == filename {
+			return &b.Media[i]
+		}
+	}
+	return nil
+}
+
+// heroFromFields describes the hero image as a media item from the blog's
+// image fields, which don't record its size
+func (b *Blog) heroFromFields() MediaItem {
+	return MediaItem{
+		Filename:  b.Image,
+		MimeType:  b.ImageMimeType,
+		Variants:  b.ImageVariants,
+		Thumbnail: b.ImageThumbnail,
+		Created:   b.Updated,
+	}
+}
@@ -0,0 +1,258 @@
+package models
+
+import (
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MediaGCGracePeriod is how long an asset is kept after its last upload even
+// if no post uses it, so garbage collection can't remove one that is about to
+// be attached to a post
+const MediaGCGracePeriod = time.Hour
+
+// assetBaseName is the name, before any variant suffix and extension, of
+// every asset's files in the media library
+const assetBaseName = "image"
+
+// MediaLibrary is implemented by blog stores that keep uploaded images in a
+// shared library, stored once per distinct upload however many posts use
+// them. Assets are keyed by the SHA-256 of the uploaded file; posts refer to
+// them from their media items (MediaItem.Hash).
+type MediaLibrary interface {
+	// SaveAsset stores asset with its files, keyed by library filename. If
+	// the library already has an asset with the same hash, that asset is kept
+	// (and marked as uploaded now) and returned instead.
+	SaveAsset(asset MediaAsset, files map[string][]byte) (*MediaAsset, error)
+	GetAsset(hash string) (*MediaAsset, error)
+	GetAssetFile(hash string, filename string) ([]byte, error)
+	// ListAssets returns every asset, most recently created first
+	ListAssets() ([]MediaAsset, error)
+	// AssetUsage returns the media items of posts, live or in the trash,
+	// that use the asset
+	AssetUsage(hash string) ([]MediaUsage, error)
+	// CollectGarbage deletes the assets no post uses that were last uploaded
+	// before uploadedBefore, returning how many it deleted
+	CollectGarbage(uploadedBefore time.Time) (int, error)
+}
+
+// MediaAsset is an uploaded image in the media library, with the variants
+// and thumbnail made from it. Its files are named image.jpg, image-640w.jpg,
+// image-thumb.jpg and so on.
+type MediaAsset struct {
+	Hash        string         `json:"hash"` // SHA-256 of the uploaded file, hex encoded
+	Filename    string         `json:"filename"`
+	MimeType    string         `json:"mime_type"`
+	Width       int            `json:"width"`
+	Height      int            `json:"height"`
+	Variants    []ImageVariant `json:"variants"` // Narrowest first
+	Thumbnail   string         `json:"thumbnail"`
+	Original    string         `json:"original,omitempty"` // The upload without its metadata, if kept
+	Placeholder string         `json:"placeholder"`        // Tiny PNG preview as a data URI
+	Color       string         `json:"color"`              // Dominant color as #rrggbb
+	Size        int64          `json:"size"`               // Bytes stored for all of its files
+	Created     time.Time      `json:"created"`
+	Uploaded    time.Time      `json:"uploaded"`  // Last time the same file was uploaded
+	RefCount    int            `json:"ref_count"` // Posts using it; filled in by the library
+}
+
+// MediaUsage is a media item of a post that uses a library asset
+type MediaUsage struct {
+	BlogID   uuid.UUID `json:"blog_id"`
+	Slug     string    `json:"slug"`
+	Title    string    `json:"title"`
+	Filename string    `json:"filename"` // The item's filename within the post
+	Trashed  bool      `json:"trashed"`
+}
+
+// MediaAssetResponse represents a library asset sent to clients
+type MediaAssetResponse struct {
+	Hash         string         `json:"hash"`
+	URL          string         `json:"url"`
+	ThumbnailURL string         `json:"thumbnail_url"`
+	OriginalURL  string         `json:"original_url"` // "" unless the original was kept
+	Placeholder  string         `json:"placeholder"`
+	Color        string         `json:"color"`
+	MimeType     string         `json:"mime_type"`
+	Width        int            `json:"width"`
+	Height       int            `json:"height"`
+	Variants     []ImageVariant `json:"variants"`
+	Size         int64          `json:"size"`
+	RefCount     int            `json:"ref_count"`
+	Created      string         `json:"created"`
+	Uploaded     string         `json:"uploaded"`
+}
+
+// MediaUsageResponse represents a post's use of a library asset sent to
+// clients
+type MediaUsageResponse struct {
+	BlogID   string `json:"blog_id"`
+	Slug     string `json:"slug"`
+	Title    string `json:"title"`
+	Filename string `json:"filename"`
+	Trashed  bool   `json:"trashed"`
+}
+
+// MediaGCResponse reports the result of a media garbage collection
+type MediaGCResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+// NewMediaAsset describes item, uploaded as the file with hash, as a library
+// asset. Its files are renamed with item.AssetFile.
+func NewMediaAsset(hash string, item MediaItem) MediaAsset {
+	asset := MediaAsset{
+		Hash:     hash,
+		Filename: item.AssetFile(item.Filename),
+		MimeType: item.MimeType,
+		Width:    item.Width,
+		Height:   item.Height,
+
+		Placeholder: item.Placeholder,
+		Color:       item.Color,
+	}
+	for _, variant := range item.Variants {
+		variant.Filename = item.AssetFile(variant.Filename)
+		asset.Variants = append(asset.Variants, variant)
+	}
+	if item.Thumbnail != "" {
+		asset.Thumbnail = item.AssetFile(item.Thumbnail)
+	}
+	return asset
+}
+
+// Files returns the filenames of the asset's image, variants, thumbnail and
+// original
+func (a *MediaAsset) Files() []string {
+	item := MediaItem{Filename: a.Filename, Variants: a.Variants, Thumbnail: a.Thumbnail}
+	files := item.Files()
+	if a.Original != "" {
+		files = append(files, a.Original)
+	}
+	return files
+}
+
+// HasFile reports whether filename is one of the asset's files
+func (a *MediaAsset) HasFile(filename string) bool {
+	for _, file := range a.Files() {
+		if file == filename {
+			return true
+		}
+	}
+	return false
+}
+
+// SourceFile returns the filename to make new copies of the asset from: the
+// original if it was kept, otherwise the widest copy
+func (a *MediaAsset) SourceFile() string {
+	if a.Original != "" {
+		return a.Original
+	}
+	largest, width := a.Filename, a.Width
+	for _, variant := range a.Variants {
+		if variant.Width > width {
+			largest, width = variant.Filename, variant.Width
+		}
+	}
+	return largest
+}
+
+// MediaItem describes the asset as a post's media item whose files are named
+// after name: photo gives photo.jpg, photo-640w.jpg and so on
+func (a *MediaAsset) MediaItem(name string) MediaItem {
+	rename := func(filename string) string {
+		return name + strings.TrimPrefix(filename, assetBaseName)
+	}
+
+	item := MediaItem{
+		Hash:     a.Hash,
+		Filename: rename(a.Filename),
+		MimeType: a.MimeType,
+		Width:    a.Width,
+		Height:   a.Height,
+
+		Placeholder: a.Placeholder,
+		Color:       a.Color,
+	}
+	for _, variant := range a.Variants {
+		variant.Filename = rename(variant.Filename)
+		item.Variants = append(item.Variants, variant)
+	}
+	if a.Thumbnail != "" {
+		item.Thumbnail = rename(a.Thumbnail)
+	}
+	return item
+}
+
+// ToResponse converts the asset for clients
+func (a *MediaAsset) ToResponse() MediaAssetResponse {
+	variants := a.Variants
+	if variants == nil {
+		variants = []ImageVariant{}
+	}
+	thumbnailURL := ""
+	if a.Thumbnail != "" {
+		thumbnailURL = AssetURL(a.Hash, a.Thumbnail)
+	}
+	originalURL := ""
+	if a.Original != "" {
+		originalURL = AssetURL(a.Hash, a.Original)
+	}
+
+	return MediaAssetResponse{
+		Hash:         a.Hash,
+		URL:          AssetURL(a.Hash, a.Filename),
+		ThumbnailURL: thumbnailURL,
+		OriginalURL:  originalURL,
+		Placeholder:  a.Placeholder,
+		Color:        a.Color,
+		MimeType:     a.MimeType,
+		Width:        a.Width,
+		Height:       a.Height,
+		Variants:     variants,
+		Size:         a.Size,
+		RefCount:     a.RefCount,
+		Created:      a.Created.Format(time.RFC3339),
+		Uploaded:     a.Uploaded.Format(time.RFC3339),
+	}
+}
+
+// ToResponse converts the usage for clients
+func (u *MediaUsage) ToResponse() MediaUsageResponse {
+	return MediaUsageResponse{
+		BlogID:   u.BlogID.String(),
+		Slug:     u.Slug,
+		Title:    u.Title,
+		Filename: u.Filename,
+		Trashed:  u.Trashed,
+	}
+}
+
+// AssetFile returns the library filename of filename, one of the item's
+// files: photo-640w.jpg is stored as image-640w.jpg
+func (m *MediaItem) AssetFile(filename string) string {
+	base := strings.TrimSuffix(m.Filename, path.Ext(m.Filename))
+	return assetBaseName + strings.TrimPrefix(filename, base)
+}
+
+// ValidMediaHash reports whether hash looks like an asset hash: 64 lowercase
+// hex digits
+func ValidMediaHash(hash string) bool {
+	if len(hash) != 64 {
+		return false
+	}
+	for _, c := range hash {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// AssetURL returns the path a library file is served at
+func AssetURL(hash string, filename string) string {
+	return "/api/media/" + url.PathEscape(hash) + "/" + url.PathEscape(filename)
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Lifetimes of draft preview links
+const (
+	DefaultPreviewLinkTTL = 72 * time.Hour
+	MaxPreviewLinkTTL     = 30 * 24 * time.Hour
+)
+
+// CreatePreviewLinkRequest represents the data needed to create a preview
+// link for an unpublished blog
+type CreatePreviewLinkRequest struct {
+	ExpiresInHours int `json:"expires_in_hours"` // 0 for the default lifetime
+}
+
+// PreviewLinkResponse represents a preview link sent to clients
+type PreviewLinkResponse struct {
+	URL       string `json:"url"` // Path of the blog page with the preview token
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// TTL returns how long the requested link stays valid
+func (req *CreatePreviewLinkRequest) TTL() time.Duration {
+	if req.ExpiresInHours == 0 {
+		return DefaultPreviewLinkTTL
+	}
+	return time.Duration(req.ExpiresInHours) * time.Hour
+}
+
+// Validate validates a create preview link request
+func (req *CreatePreviewLinkRequest) Validate() error {
+	if req.ExpiresInHours < 0 || req.TTL() > MaxPreviewLinkTTL {
+		return &ValidationError{
+			Field:   "expires_in_hours",
+			Message: fmt.Sprintf("expires_in_hours must be between 1 and %d", int(MaxPreviewLinkTTL/time.Hour)),
+		}
+	}
+	return nil
+}
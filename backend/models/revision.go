@@ -0,0 +1,151 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevisionStore is implemented by blog stores that keep a history of every
+// update made to a blog
+type RevisionStore interface {
+	ListRevisions(slug string) ([]Revision, error)
+	GetRevision(slug string, revisionID int) (*Revision, error)
+	RestoreRevision(slug string, revisionID int) (*Blog, error)
+}
+
+// Revision records the state of a blog just before an update was applied
+type Revision struct {
+	ID            int       `json:"id"` // Sequential per blog, starting at 1
+	BlogID        uuid.UUID `json:"blog_id"`
+	Timestamp     time.Time `json:"timestamp"`
+	ChangedFields []string  `json:"changed_fields"` // JSON names of the fields the update changed
+	Previous      Blog      `json:"previous"`       // The blog as it was before the update
+}
+
+// RevisionResponse represents a revision sent to clients
+type RevisionResponse struct {
+	ID            int           `json:"id"`
+	BlogID        string        `json:"blog_id"`
+	Timestamp     string        `json:"timestamp"`
+	ChangedFields []string      `json:"changed_fields"`
+	Previous      *BlogResponse `json:"previous,omitempty"` // Omitted in revision listings
+}
+
+// RevisionDiffResponse represents a unified diff between two versions of a blog
+type RevisionDiffResponse struct {
+	From          string   `json:"from"` // Revision ID or "current"
+	To            string   `json:"to"`   // Revision ID or "current"
+	ChangedFields []string `json:"changed_fields"`
+	Diff          string   `json:"diff"` // Unified diff of the content
+}
+
+// ToResponse converts a Revision to a RevisionResponse, including the
+// previous blog state when withPrevious is set
+func (r *Revision) ToResponse(withPrevious bool) RevisionResponse {
+	response := RevisionResponse{
+		ID:            r.ID,
+		BlogID:        r.BlogID.String(),
+		Timestamp:     r.Timestamp.Format(time.RFC3339),
+		ChangedFields: r.ChangedFields,
+	}
+	if withPrevious {
+		previous := r.Previous.ToResponse()
+		response.Previous = &previous
+	}
+	return response
+}
+
+// ChangedFields returns the JSON names of the editable fields that differ
+// between two versions of a blog
+func ChangedFields(before Blog, after Blog) []string {
+	changed := []string{}
+	if before.Title != after.Title {
+		changed = append(changed, "title")
+	}
+	if before.Content != after.Content {
+		changed = append(changed, "content")
+	}
+	if before.Image != after.Image {
+		changed = append(changed, "image")
+	}
+	if before.AuthorName != after.AuthorName {
+		changed = append(changed, "author_name")
+	}
+	if before.AuthorUsername != after.AuthorUsername {
+		changed = append(changed, "author_username")
+	}
+	if before.MetaName != after.MetaName {
+		changed = append(changed, "meta_name")
+	}
+	if before.MetaDescription != after.MetaDescription {
+		changed = append(changed, "meta_description")
+	}
+	if strings.Join(before.Tags, ",") != strings.Join(after.Tags, ",") {
+		changed = append(changed, "tags")
+	}
+	if before.Category != after.Category {
+		changed = append(changed, "category")
+	}
+	if before.Slug != after.Slug {
+		changed = append(changed, "slug")
+	}
+	if before.Published != after.Published {
+		changed = append(changed, "published")
+	}
+	if !sameSchedule(before.PublishAt, after.PublishAt) {
+		changed = append(changed, "publish_at")
+	}
+	if !sameSchedule(before.UnpublishAt, after.UnpublishAt) {
+		changed = append(changed, "unpublish_at")
+	}
+	return changed
+}
+
+// RestoreRequest builds the update that rolls current back to this revision.
+// The hero image is left alone because replaced image files are not kept, but
+// media library images the revision had that have since left the collection
+// are added back, so content embedding them still resolves.
+func (r *Revision) RestoreRequest(current *Blog) UpdateBlogRequest {
+	previous := r.Previous
+	req := UpdateBlogRequest{
+		Title:           &previous.Title,
+		Content:         &previous.Content,
+		AuthorName:      &previous.AuthorName,
+		AuthorUsername:  &previous.AuthorUsername,
+		MetaName:        &previous.MetaName,
+		MetaDescription: &previous.MetaDescription,
+		Tags:            &previous.Tags,
+		Category:        &previous.Category,
+		Slug:            &previous.Slug,
+		Published:       &previous.Published,
+		PublishAt:       scheduleUpdate(previous.PublishAt),
+		UnpublishAt:     scheduleUpdate(previous.UnpublishAt),
+	}
+	if media, added := restoredMedia(current.Media, previous.Media); added {
+		req.Media = &media
+	}
+	return req
+}
+
+// restoredMedia returns current followed by the items of previous whose
+// filenames it lacks, up to MaxMediaItems, and whether there were any. Only
+// media library items come back; the files of items stored with the blog
+// were deleted when they were removed.
+func restoredMedia(current []MediaItem, previous []MediaItem) ([]MediaItem, bool) {
+	filenames := make(map[string]bool, len(current))
+	for _, item := range current {
+		filenames[item.Filename] = true
+	}
+
+	media := append([]MediaItem{}, current...)
+	for _, item := range previous {
+		if item.Hash == "" || filenames[item.Filename] || len(media) >= MaxMediaItems {
+			continue
+		}
+		media = append(media, item)
+		filenames[item.Filename] = true
+	}
+	return media, len(media) > len(current)
+}
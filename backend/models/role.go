@@ -0,0 +1,77 @@
+package models
+
+import "strings"
+
+// User roles, from most to least privileged
+const (
+	RoleAdmin  = "admin"  // everything, including managing users
+	RoleEditor = "editor" // edit, publish and delete any blog
+	RoleAuthor = "author" // write and delete their own blogs, as drafts
+	RoleViewer = "viewer" // read only
+)
+
+// Roles lists every valid role
+var Roles = []string{RoleAdmin, RoleEditor, RoleAuthor, RoleViewer}
+
+// Permission is an action a role may be allowed to perform
+type Permission string
+
+// Permissions granted through roles
+const (
+	PermissionCreateBlogs    Permission = "blogs:create"
+	PermissionEditOwnBlogs   Permission = "blogs:edit-own"
+	PermissionEditAnyBlog    Permission = "blogs:edit-any"
+	PermissionDeleteOwnBlogs Permission = "blogs:delete-own"
+	PermissionDeleteAnyBlog  Permission = "blogs:delete-any"
+	PermissionPublishBlogs   Permission = "blogs:publish"
+	PermissionManageUsers    Permission = "users:manage"
+)
+
+// rolePermissions maps each role to the permissions it grants
+var rolePermissions = map[string][]Permission{
+	RoleAdmin: {
+		PermissionCreateBlogs, PermissionEditOwnBlogs, PermissionEditAnyBlog,
+		PermissionDeleteOwnBlogs, PermissionDeleteAnyBlog, PermissionPublishBlogs,
+		PermissionManageUsers,
+	},
+	RoleEditor: {
+		PermissionCreateBlogs, PermissionEditOwnBlogs, PermissionEditAnyBlog,
+		PermissionDeleteOwnBlogs, PermissionDeleteAnyBlog, PermissionPublishBlogs,
+	},
+	RoleAuthor: {
+		PermissionCreateBlogs, PermissionEditOwnBlogs, PermissionDeleteOwnBlogs,
+	},
+	RoleViewer: {},
+}
+
+// ValidRole reports whether role is one of Roles
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// Can reports whether the user's role grants permission. Unknown roles grant
+// nothing.
+func (u *User) Can(permission Permission) bool {
+	for _, granted := range rolePermissions[u.Role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Owns reports whether the user is the blog's author
+func (u *User) Owns(blog *Blog) bool {
+	return strings.EqualFold(blog.AuthorUsername, u.Username)
+}
+
+// CanEditBlog reports whether the user may update the blog
+func (u *User) CanEditBlog(blog *Blog) bool {
+	return u.Can(PermissionEditAnyBlog) || (u.Can(PermissionEditOwnBlogs) && u.Owns(blog))
+}
+
+// CanDeleteBlog reports whether the user may delete (or restore) the blog
+func (u *User) CanDeleteBlog(blog *Blog) bool {
+	return u.Can(PermissionDeleteAnyBlog) || (u.Can(PermissionDeleteOwnBlogs) && u.Owns(blog))
+}
@@ -0,0 +1,112 @@
+package models
+
+import "time"
+
+// IsLive reports whether the blog is publicly visible at now. A blog is live
+// once it is published or its publish_at time has passed, until its
+// unpublish_at time (if any) arrives.
+func (b *Blog) IsLive(now time.Time) bool {
+	if !b.Published && b.PublishAt == nil {
+		return false
+	}
+	if b.PublishAt != nil && now.Before(*b.PublishAt) {
+		return false
+	}
+	if b.UnpublishAt != nil && !now.Before(*b.UnpublishAt) {
+		return false
+	}
+	return true
+}
+
+// IsScheduled reports whether the blog is waiting for its publish_at time
+func (b *Blog) IsScheduled(now time.Time) bool {
+	return b.PublishAt != nil && now.Before(*b.PublishAt)
+}
+
+// NextTransition returns the next time the blog's visibility is due to
+// change, or the zero time if nothing is scheduled
+func (b *Blog) NextTransition() time.Time {
+	var next time.Time
+	if b.PublishAt != nil {
+		next = *b.PublishAt
+	}
+	if b.UnpublishAt != nil && (next.IsZero() || b.UnpublishAt.Before(next)) {
+		next = *b.UnpublishAt
+	}
+	return next
+}
+
+// DueTransition builds the update that applies every schedule of the blog
+// whose time has come by now: a due publish_at publishes the blog and a due
+// unpublish_at unpublishes it, and both are cleared once applied. It reports
+// false if nothing is due.
+func (b *Blog) DueTransition(now time.Time) (UpdateBlogRequest, bool) {
+	var updates UpdateBlogRequest
+	cleared := time.Time{}
+	due := false
+
+	if b.PublishAt != nil && !now.Before(*b.PublishAt) {
+		published := true
+		updates.Published = &published
+		updates.PublishAt = &cleared
+		due = true
+	}
+	if b.UnpublishAt != nil && !now.Before(*b.UnpublishAt) {
+		published := false
+		updates.Published = &published
+		updates.UnpublishAt = &cleared
+		due = true
+	}
+	return updates, due
+}
+
+// ApplySchedule sets a schedule field from an update value, where the zero
+// time clears it
+func ApplySchedule(field **time.Time, value *time.Time) {
+	if value == nil {
+		return
+	}
+	if value.IsZero() {
+		*field = nil
+		return
+	}
+	t := value.UTC()
+	*field = &t
+}
+
+// ValidateSchedule checks that a blog isn't unpublished before it is
+// published. Either time may be nil.
+func ValidateSchedule(publishAt, unpublishAt *time.Time) error {
+	if publishAt != nil && unpublishAt != nil && !unpublishAt.After(*publishAt) {
+		return &ValidationError{Field: "unpublish_at", Message: "unpublish_at must be after publish_at"}
+	}
+	return nil
+}
+
+// formatSchedule formats a schedule time for responses, or returns nil
+func formatSchedule(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format(time.RFC3339)
+	return &formatted
+}
+
+// scheduleUpdate returns the update value that sets a schedule field to t,
+// using the zero time to clear it
+func scheduleUpdate(t *time.Time) *time.Time {
+	if t == nil {
+		return &time.Time{}
+	}
+	value := *t
+	return &value
+}
+
+// sameSchedule reports whether two schedule times are equal, treating nil
+// as unset
+func sameSchedule(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
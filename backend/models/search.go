@@ -0,0 +1,26 @@
+package models
+
+// SearchResultResponse represents a blog matching a search, sent to clients
+type SearchResultResponse struct {
+	ID              string   `json:"id"`
+	Slug            string   `json:"slug"`
+	Title           string   `json:"title"`
+	MetaDescription string   `json:"meta_description"`
+	Image           string   `json:"image"`
+	AuthorName      string   `json:"author_name"`
+	Tags            []string `json:"tags"`
+	Category        string   `json:"category"`
+	Created         string   `json:"created"`
+	Snippet         string   `json:"snippet"` // HTML-escaped excerpt, matches wrapped in <mark>
+	Score           float64  `json:"score"`
+}
+
+// SearchResponse represents one page of search results sent to clients
+type SearchResponse struct {
+	Query      string                 `json:"query"`
+	Results    []SearchResultResponse `json:"results"`
+	Page       int                    `json:"page"`
+	PerPage    int                    `json:"per_page"`
+	Total      int                    `json:"total"`
+	TotalPages int                    `json:"total_pages"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// SlugHistoryStore is implemented by blog stores that remember the slugs a
+// blog was previously published under, so old URLs can be redirected
+type SlugHistoryStore interface {
+	// ResolveSlug returns the blog that was most recently published under a
+	// former slug, or a "blog not found" error if no blog ever used it
+	ResolveSlug(slug string) (*Blog, error)
+}
+
+// SlugChange records a slug a blog moved away from
+type SlugChange struct {
+	Slug      string    `json:"slug"`
+	ChangedAt time.Time `json:"changed_at"`
+}
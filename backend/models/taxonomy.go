@@ -0,0 +1,104 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Limits on a blog's tags and category
+const (
+	MaxTags           = 20
+	MaxTagLength      = 50
+	MaxCategoryLength = 100
+)
+
+// TagCount is a tag and the number of blogs using it
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// NormalizeTags lowercases and trims tags, collapses inner whitespace and
+// drops empty and duplicate tags, keeping the first occurrence's position.
+// The result is never nil.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// NormalizeCategory trims a category and collapses inner whitespace
+func NormalizeCategory(category string) string {
+	return strings.Join(strings.Fields(category), " ")
+}
+
+// ValidateTaxonomy checks normalized tags and a category against the limits
+func ValidateTaxonomy(tags []string, category string) error {
+	if len(tags) > MaxTags {
+		return &ValidationError{Field: "tags", Message: fmt.Sprintf("A blog can have at most %d tags", MaxTags)}
+	}
+	for _, tag := range tags {
+		if len(tag) > MaxTagLength {
+			return &ValidationError{Field: "tags", Message: fmt.Sprintf("Tags must be at most %d characters", MaxTagLength)}
+		}
+		if strings.ContainsAny(tag, ",/") {
+			return &ValidationError{Field: "tags", Message: "Tags must not contain commas or slashes"}
+		}
+	}
+	if len(category) > MaxCategoryLength {
+		return &ValidationError{Field: "category", Message: fmt.Sprintf("Category must be at most %d characters", MaxCategoryLength)}
+	}
+	if strings.Contains(category, "/") {
+		return &ValidationError{Field: "category", Message: "Category must not contain slashes"}
+	}
+	return nil
+}
+
+// HasTag reports whether the blog is tagged with tag, ignoring case
+func (b *Blog) HasTag(tag string) bool {
+	for _, t := range b.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// InCategory reports whether the blog belongs to category, ignoring case.
+// Blogs without a category belong to none.
+func (b *Blog) InCategory(category string) bool {
+	return b.Category != "" && strings.EqualFold(b.Category, category)
+}
+
+// CountTags counts how many of the given blogs use each tag, most used
+// first and alphabetically among equals
+func CountTags(blogs []Blog) []TagCount {
+	counts := make(map[string]int)
+	for _, blog := range blogs {
+		for _, tag := range blog.Tags {
+			counts[tag]++
+		}
+	}
+
+	tagCounts := make([]TagCount, 0, len(counts))
+	for tag, count := range counts {
+		tagCounts = append(tagCounts, TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(tagCounts, func(i, j int) bool {
+		if tagCounts[i].Count != tagCounts[j].Count {
+			return tagCounts[i].Count > tagCounts[j].Count
+		}
+		return tagCounts[i].Tag < tagCounts[j].Tag
+	})
+
+	return tagCounts
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APITokenStore represents the storage interface for personal API tokens.
+// Tokens are looked up by a hash; the token itself is never stored.
+type APITokenStore interface {
+	GetAPITokensByUser(userID uuid.UUID) ([]APIToken, error)
+	GetAPITokenByHash(tokenHash string) (*APIToken, error)
+	CreateAPIToken(token APIToken) (APIToken, error)
+	DeleteAPIToken(userID uuid.UUID, id uuid.UUID) error
+	TouchAPIToken(id uuid.UUID, usedAt time.Time) error
+}
+
+// API token scopes. A token may only call routes requiring one of its scopes;
+// browser sessions are not limited by scopes.
+const (
+	ScopeBlogsWrite  = "blogs:write"  // create, update and restore blogs
+	ScopeBlogsDelete = "blogs:delete" // delete blogs and manage the trash
+	ScopeImagesWrite = "images:write" // upload blog images
+)
+
+// APITokenScopes lists every scope a token can be granted
+var APITokenScopes = []string{ScopeBlogsWrite, ScopeBlogsDelete, ScopeImagesWrite}
+
+// APIToken represents a personal API token used for automation
+type APIToken struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`     // first characters of the token, to tell tokens apart
+	TokenHash string     `json:"token_hash"` // SHA-256 of the token
+	Scopes    []string   `json:"scopes"`
+	Created   time.Time  `json:"created"`
+	LastUsed  *time.Time `json:"last_used,omitempty"`
+}
+
+// HasScope reports whether the token was granted scope
+func (t *APIToken) HasScope(scope string) bool {
+	for _, granted := range t.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAPITokenRequest represents the data needed to create an API token
+type CreateAPITokenRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// Validate validates a create API token request
+func (req *CreateAPITokenRequest) Validate() error {
+	if req.Name == "" {
+		return &ValidationError{Field: "name", Message: "Name is required"}
+	}
+	if len(req.Scopes) == 0 {
+		return &ValidationError{Field: "scopes", Message: "At least one scope is required"}
+	}
+	for _, scope := range req.Scopes {
+		valid := false
+		for _, known := range APITokenScopes {
+			if scope == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return &ValidationError{Field: "scopes", Message: "Unknown scope: " + scope}
+		}
+	}
+	return nil
+}
+
+// APITokenResponse represents the API token data sent to clients. Token is
+// only set in the response to creating the token.
+type APITokenResponse struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Prefix   string   `json:"prefix"`
+	Scopes   []string `json:"scopes"`
+	Created  string   `json:"created"`
+	LastUsed string   `json:"last_used,omitempty"`
+	Token    string   `json:"token,omitempty"`
+}
+
+// Convert APIToken to APITokenResponse, leaving out the token hash
+func (t *APIToken) ToResponse() APITokenResponse {
+	response := APITokenResponse{
+		ID:      t.ID.String(),
+		Name:    t.Name,
+		Prefix:  t.Prefix,
+		Scopes:  t.Scopes,
+		Created: t.Created.Format(time.RFC3339),
+	}
+	if t.LastUsed != nil {
+		response.LastUsed = t.LastUsed.Format(time.RFC3339)
+	}
+	return response
+}
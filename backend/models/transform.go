@@ -0,0 +1,29 @@
+package models
+
+import "net/url"
+
+// CreateImageTransformRequest represents the data needed to create a signed
+// URL that resizes or crops a media library asset. Width or height may be
+// left out to keep the asset's aspect ratio.
+type CreateImageTransformRequest struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Fit    string `json:"fit"`    // contain (default), cover or crop
+	Format string `json:"format"` // auto (default), jpeg or png
+}
+
+// ImageTransformResponse represents a signed image transformation URL sent
+// to clients
+type ImageTransformResponse struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Fit    string `json:"fit"`
+	Format string `json:"format"`
+}
+
+// TransformURL returns the path that serves the asset with hash transformed
+// as query describes, with its signature
+func TransformURL(hash string, query string, signature string) string {
+	return "/api/media/" + url.PathEscape(hash) + "/transform?" + query + "&s=" + url.QueryEscape(signature)
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TrashStore is implemented by blog stores whose DeleteBlogBySlug moves blogs
+// to a trash area instead of removing them permanently
+type TrashStore interface {
+	ListTrash() ([]TrashedBlog, error)
+	RestoreTrashedBlog(id uuid.UUID) (*Blog, error)
+	PurgeTrash(deletedBefore time.Time) (int, error)
+}
+
+// TrashedBlog is a deleted blog waiting in the trash
+type TrashedBlog struct {
+	Blog      Blog      `json:"blog"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// TrashedBlogResponse represents a trashed blog sent to clients
+type TrashedBlogResponse struct {
+	Blog      BlogResponse `json:"blog"`
+	DeletedAt string       `json:"deleted_at"`
+}
+
+// ToResponse converts a TrashedBlog to a TrashedBlogResponse
+func (t *TrashedBlog) ToResponse() TrashedBlogResponse {
+	return TrashedBlogResponse{
+		Blog:      t.Blog.ToResponse(),
+		DeletedAt: t.DeletedAt.Format(time.RFC3339),
+	}
+}
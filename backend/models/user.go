@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserStore represents the storage interface for user accounts
+type UserStore interface {
+	GetAllUsers() ([]User, error)
+	GetUserByID(id uuid.UUID) (*User, error)
+	GetUserByUsername(username string) (*User, error)
+	CreateUser(user User) (User, error)
+	UpdateUser(user User) error
+	DeleteUser(id uuid.UUID) error
+}
+
+// SessionStore represents the storage interface for login sessions. Sessions
+// are looked up by a hash of their token; the token itself is never stored.
+type SessionStore interface {
+	CreateSession(session Session) error
+	GetSession(tokenHash string) (*Session, error)
+	DeleteSession(tokenHash string) error
+	DeleteExpiredSessions(now time.Time) (int, error)
+}
+
+// User represents an account that can sign in to manage blogs
+type User struct {
+	ID           uuid.UUID `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"password_hash"` // bcrypt hash
+	Role         string    `json:"role"`
+	Created      time.Time `json:"created"`
+}
+
+// Session represents a signed-in user's session
+type Session struct {
+	TokenHash string    `json:"token_hash"` // SHA-256 of the session cookie value
+	UserID    uuid.UUID `json:"user_id"`
+	Created   time.Time `json:"created"`
+	Expires   time.Time `json:"expires"`
+}
+
+// LoginRequest represents the credentials sent to log in
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// CreateUserRequest represents the data needed to create a user
+type CreateUserRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+// UpdateUserRequest represents the data needed to update a user
+type UpdateUserRequest struct {
+	Role     *string `json:"role,omitempty"`
+	Password *string `json:"password,omitempty"`
+}
+
+// UserResponse represents the user data sent to clients
+type UserResponse struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	Created  string `json:"created"`
+}
+
+// Convert User to UserResponse, leaving out the password hash
+func (u *User) ToResponse() UserResponse {
+	return UserResponse{
+		ID:       u.ID.String(),
+		Username: u.Username,
+		Role:     u.Role,
+		Created:  u.Created.Format(time.RFC3339),
+	}
+}
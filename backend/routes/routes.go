@@ -5,14 +5,33 @@ import (
 	"net/http"
 	"time"
 
+	"go-react-backend/auth"
 	"go-react-backend/handlers"
+	"go-react-backend/middleware"
+	"go-react-backend/models"
 
 	"github.com/gorilla/mux"
 )
 
-// SetupRoutes configures all the routes for the application
-func SetupRoutes(blogHandler *handlers.BlogHandler) *mux.Router {
+// SetupRoutes configures all the routes for the application. Routes that
+// change data require a signed-in user or an API token with the route's scope;
+// handlers then check the user's role. Blog reads only show unpublished blogs
+// to signed-in users who can edit them.
+func SetupRoutes(blogHandler *handlers.BlogHandler, authHandler *handlers.AuthHandler, searchHandler *handlers.SearchHandler, previewHandler *handlers.PreviewHandler, transformHandler *handlers.TransformHandler, authService *auth.Service) *mux.Router {
 	r := mux.NewRouter()
+	requireAuth := middleware.RequireAuth(authService)
+	optionalAuth := middleware.OptionalAuth(authService)
+	requireImageScope := middleware.RequireFileScope("image", models.ScopeImagesWrite)
+	
+	// withScope guards a handler with authentication and an API token scope
+	withScope := func(scope string, handler http.Handler) http.Handler {
+		return requireAuth(middleware.RequireScope(scope)(handler))
+	}
+	
+	// sessionOnly guards a handler that API tokens may not call at all
+	sessionOnly := func(handler http.Handler) http.Handler {
+		return requireAuth(middleware.RequireSession(handler))
+	}
 
 	// API routes
 	api := r.PathPrefix("/api").Subrouter()
@@ -20,13 +39,70 @@ func SetupRoutes(blogHandler *handlers.BlogHandler) *mux.Router {
 	// Health check endpoint
 	api.HandleFunc("/health", healthHandler).Methods("GET")
 	
-	// Blog endpoints (write operations only - read data is embedded in HTML)
-	api.HandleFunc("/blogs", blogHandler.CreateBlog).Methods("POST")
-	api.HandleFunc("/blogs/{slug}", blogHandler.UpdateBlogBySlug).Methods("PUT")
-	api.HandleFunc("/blogs/{slug}", blogHandler.DeleteBlogBySlug).Methods("DELETE")
+	// Blog read endpoints (SSR pages also embed blog data in HTML)
+	api.Handle("/blogs", optionalAuth(http.HandlerFunc(blogHandler.GetBlogs))).Methods("GET")
+	api.Handle("/blogs/id/{id}", optionalAuth(http.HandlerFunc(blogHandler.GetBlogByID))).Methods("GET")
+	api.Handle("/blogs/{slug}", optionalAuth(http.HandlerFunc(blogHandler.GetBlogBySlug))).Methods("GET")
+	api.HandleFunc("/tags", blogHandler.GetTags).Methods("GET")
+	api.HandleFunc("/search", searchHandler.Search).Methods("GET")
+	
+	// Auth endpoints
+	api.HandleFunc("/auth/login", authHandler.Login).Methods("POST")
+	api.HandleFunc("/auth/logout", authHandler.Logout).Methods("POST")
+	api.Handle("/auth/me", requireAuth(http.HandlerFunc(authHandler.Me))).Methods("GET")
+	
+	// API token endpoints (managed from a browser session only)
+	api.Handle("/tokens", sessionOnly(http.HandlerFunc(authHandler.ListAPITokens))).Methods("GET")
+	api.Handle("/tokens", sessionOnly(http.HandlerFunc(authHandler.CreateAPIToken))).Methods("POST")
+	api.Handle("/tokens/{id}", sessionOnly(http.HandlerFunc(authHandler.RevokeAPIToken))).Methods("DELETE")
+	
+	// User management endpoints (admins, from a browser session only)
+	manageUsers := middleware.RequirePermission(models.PermissionManageUsers)
+	api.Handle("/users", sessionOnly(manageUsers(http.HandlerFunc(authHandler.ListUsers)))).Methods("GET")
+	api.Handle("/users", sessionOnly(manageUsers(http.HandlerFunc(authHandler.CreateUser)))).Methods("POST")
+	api.Handle("/users/{id}", sessionOnly(manageUsers(http.HandlerFunc(authHandler.UpdateUser)))).Methods("PUT")
+	api.Handle("/users/{id}", sessionOnly(manageUsers(http.HandlerFunc(authHandler.DeleteUser)))).Methods("DELETE")
+	
+	// Blog write endpoints; uploading an image also needs images:write
+	api.Handle("/blogs", withScope(models.ScopeBlogsWrite, requireImageScope(http.HandlerFunc(blogHandler.CreateBlog)))).Methods("POST")
+	api.Handle("/blogs/{slug}", withScope(models.ScopeBlogsWrite, requireImageScope(http.HandlerFunc(blogHandler.UpdateBlogBySlug)))).Methods("PUT")
+	api.Handle("/blogs/{slug}", withScope(models.ScopeBlogsDelete, http.HandlerFunc(blogHandler.DeleteBlogBySlug))).Methods("DELETE")
+	
+	// Signed preview links for unpublished blogs
+	api.Handle("/blogs/{slug}/preview", withScope(models.ScopeBlogsWrite, http.HandlerFunc(previewHandler.CreatePreviewLink))).Methods("POST")
+	
+	// Media collection routes; adding or deleting files also needs images:write
+	api.Handle("/blogs/{slug}/media", optionalAuth(http.HandlerFunc(blogHandler.ListMedia))).Methods("GET")
+	api.Handle("/blogs/{slug}/media", withScope(models.ScopeBlogsWrite, requireImageScope(http.HandlerFunc(blogHandler.UploadMedia)))).Methods("POST")
+	api.Handle("/blogs/{slug}/media/{filename}", withScope(models.ScopeBlogsWrite, http.HandlerFunc(blogHandler.UpdateMedia))).Methods("PUT")
+	api.Handle("/blogs/{slug}/media/{filename}", withScope(models.ScopeBlogsWrite, middleware.RequireScope(models.ScopeImagesWrite)(http.HandlerFunc(blogHandler.DeleteMedia)))).Methods("DELETE")
+	
+	// Media library routes, for users who can write blogs; garbage collection
+	// is left to those who can delete anyone's
+	mediaUser := middleware.RequirePermission(models.PermissionCreateBlogs)
+	mediaAdmin := middleware.RequirePermission(models.PermissionDeleteAnyBlog)
+	api.Handle("/media", withScope(models.ScopeImagesWrite, mediaUser(http.HandlerFunc(blogHandler.ListMediaAssets)))).Methods("GET")
+	api.Handle("/media/gc", withScope(models.ScopeImagesWrite, mediaAdmin(http.HandlerFunc(blogHandler.CollectMediaGarbage)))).Methods("POST")
+	api.Handle("/media/{hash:[0-9a-f]{64}}/usage", withScope(models.ScopeImagesWrite, mediaUser(http.HandlerFunc(blogHandler.GetMediaAssetUsage)))).Methods("GET")
+	api.Handle("/media/{hash:[0-9a-f]{64}}/{filename:image[^/]*}", withScope(models.ScopeImagesWrite, mediaUser(http.HandlerFunc(blogHandler.ServeMediaAsset)))).Methods("GET")
+	
+	// Image transformations are signed by users who can write blogs, then
+	// served to anyone holding the URL
+	api.Handle("/media/{hash:[0-9a-f]{64}}/transforms", withScope(models.ScopeImagesWrite, mediaUser(http.HandlerFunc(transformHandler.CreateTransformURL)))).Methods("POST")
+	api.HandleFunc("/media/{hash:[0-9a-f]{64}}/transform", transformHandler.ServeTransform).Methods("GET")
+	
+	// Revision history endpoints, for users who can edit the blog
+	api.Handle("/blogs/{slug}/revisions", withScope(models.ScopeBlogsWrite, http.HandlerFunc(blogHandler.ListRevisions))).Methods("GET")
+	api.Handle("/blogs/{slug}/revisions/diff", withScope(models.ScopeBlogsWrite, http.HandlerFunc(blogHandler.DiffRevisions))).Methods("GET")
+	api.Handle("/blogs/{slug}/revisions/{id:[0-9]+}", withScope(models.ScopeBlogsWrite, http.HandlerFunc(blogHandler.GetRevision))).Methods("GET")
+	api.Handle("/blogs/{slug}/revisions/{id:[0-9]+}/restore", withScope(models.ScopeBlogsWrite, http.HandlerFunc(blogHandler.RestoreRevision))).Methods("POST")
+	
+	// Trash endpoints (deleted blogs)
+	api.Handle("/trash", withScope(models.ScopeBlogsDelete, http.HandlerFunc(blogHandler.ListTrash))).Methods("GET")
+	api.Handle("/trash/{id}/restore", withScope(models.ScopeBlogsDelete, http.HandlerFunc(blogHandler.RestoreTrashedBlog))).Methods("POST")
 	
-	// Image endpoints
-	api.HandleFunc("/images/{slug}/{filename}", blogHandler.ServeImage).Methods("GET")
+	// Image endpoints; unpublished blogs' images need an editor or a preview token
+	api.Handle("/images/{slug}/{filename}", optionalAuth(http.HandlerFunc(blogHandler.ServeImage))).Methods("GET")
 
 	return r
 }
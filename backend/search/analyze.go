@@ -0,0 +1,88 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// stopwords are common English words left out of the index and queries
+var stopwords = makeSet(`a about above after again against all am an and any are as at be because
+been before being below between both but by can could did do does doing down during each few
+for from further had has have having he her here hers herself him himself his how i if in into
+is it its itself just me more most my myself no nor not now of off on once only or other our
+ours ourselves out over own same she should so some such than that the their theirs them
+themselves then there these they this those through to too under until up very was we were
+what when where which while who whom why will with would you your yours yourself yourselves`)
+
+func makeSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+// token is a word of a text and its position in bytes
+type token struct {
+	term  string // stemmed, lowercase form used in the index
+	start int
+	end   int
+}
+
+// tokenize splits text into indexable words: runs of letters and digits,
+// lowercased and stemmed, without stopwords and single letters
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i := 0; i <= len(text); {
+		r, size := utf8.RuneError, 1
+		if i < len(text) {
+			r, size = utf8.DecodeRuneInString(text[i:])
+		}
+
+		isWord := i < len(text) && (unicode.IsLetter(r) || unicode.IsDigit(r))
+		if isWord && start < 0 {
+			start = i
+		}
+		if !isWord && start >= 0 {
+			if term, ok := normalizeTerm(text[start:i]); ok {
+				tokens = append(tokens, token{term: term, start: start, end: i})
+			}
+			start = -1
+		}
+
+		i += size
+	}
+	return tokens
+}
+
+// normalizeTerm lowercases and stems a word, reporting false for words that
+// should not be indexed
+func normalizeTerm(word string) (string, bool) {
+	word = strings.ToLower(word)
+	if stopwords[word] {
+		return "", false
+	}
+	if utf8.RuneCountInString(word) < 2 {
+		r, _ := utf8.DecodeRuneInString(word)
+		if !unicode.IsDigit(r) {
+			return "", false
+		}
+	}
+	return Stem(word), true
+}
+
+// terms returns the distinct indexable terms of text, in order of first
+// appearance
+func terms(text string) []string {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, tok := range tokenize(text) {
+		if !seen[tok.term] {
+			seen[tok.term] = true
+			unique = append(unique, tok.term)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,31 @@
+package search
+
+import (
+	"fmt"
+
+	"go-react-backend/models"
+	"go-react-backend/storage"
+)
+
+// Follow keeps index in step with the blogs in store by applying every change
+// published on events. It returns a function that stops following.
+func Follow(index *Index, store models.BlogStore, events *storage.EventBus) (stop func()) {
+	return events.Subscribe(func(event storage.ChangeEvent) {
+		if event.Type == storage.BlogDeleted {
+			index.Remove(event.ID)
+			return
+		}
+
+		blog, err := store.GetBlogByID(event.ID)
+		if err != nil {
+			if err.Error() == "blog not found" {
+				// Gone again by the time the event arrived
+				index.Remove(event.ID)
+			} else {
+				fmt.Printf("❌ Failed to reindex blog %s: %v\n", event.Slug, err)
+			}
+			return
+		}
+		index.Put(*blog)
+	})
+}
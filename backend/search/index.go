@@ -0,0 +1,244 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"go-react-backend/models"
+
+	"github.com/google/uuid"
+)
+
+// field is a part of a blog that is indexed separately
+type field int
+
+const (
+	fieldTitle field = iota
+	fieldDescription
+	fieldContent
+	numFields
+)
+
+// fieldWeights make a match in the title count more than one in the body
+var fieldWeights = [numFields]float64{3, 2, 1}
+
+// BM25 parameters: k1 limits how much repeated terms add to the score and b
+// how much longer fields are penalized
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// document is an indexed blog
+type document struct {
+	blog    models.Blog
+	text    string // content as plain text, for snippets
+	lengths [numFields]int
+	freqs   map[string]*[numFields]int // term frequencies per field
+}
+
+// Index is an in-memory inverted index of blogs, ranked with BM25F over the
+// title, meta description and content. It is safe for concurrent use.
+type Index struct {
+	mu           sync.RWMutex
+	docs         map[uuid.UUID]*document
+	postings     map[string]map[uuid.UUID]bool // term -> blogs containing it
+	totalLengths [numFields]int
+}
+
+// Options control a search
+type Options struct {
+	IncludeDrafts bool // also match blogs that aren't live
+	Offset        int  // results to skip; negative counts as zero
+	Limit         int  // maximum number of results, all if zero
+}
+
+// Result is a blog matching a search
+type Result struct {
+	Blog  models.Blog
+	Score float64
+	// Snippet is an HTML-escaped excerpt of the content with matching words
+	// wrapped in <mark>
+	Snippet string
+}
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[uuid.UUID]*document),
+		postings: make(map[string]map[uuid.UUID]bool),
+	}
+}
+
+// Load replaces the index's contents with blogs
+func (idx *Index) Load(blogs []models.Blog) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.docs = make(map[uuid.UUID]*document, len(blogs))
+	idx.postings = make(map[string]map[uuid.UUID]bool)
+	idx.totalLengths = [numFields]int{}
+	for _, blog := range blogs {
+		idx.put(blog)
+	}
+}
+
+// Put adds a blog to the index, replacing any earlier version of it
+func (idx *Index) Put(blog models.Blog) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(blog.ID)
+	idx.put(blog)
+}
+
+// Remove drops a blog from the index
+func (idx *Index) Remove(id uuid.UUID) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(id)
+}
+
+// Len returns the number of indexed blogs
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// put indexes a blog that is not in the index. The caller must hold the
+// write lock.
+func (idx *Index) put(blog models.Blog) {
+	doc := &document{
+		blog:  blog,
+		text:  plainText(blog.Content),
+		freqs: make(map[string]*[numFields]int),
+	}
+
+	fields := [numFields]string{blog.Title, blog.MetaDescription, doc.text}
+	for f, text := range fields {
+		for _, tok := range tokenize(text) {
+			freqs, ok := doc.freqs[tok.term]
+			if !ok {
+				freqs = &[numFields]int{}
+				doc.freqs[tok.term] = freqs
+			}
+			freqs[f]++
+			doc.lengths[f]++
+		}
+		idx.totalLengths[f] += doc.lengths[f]
+	}
+
+	for term := range doc.freqs {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[uuid.UUID]bool)
+		}
+		idx.postings[term][blog.ID] = true
+	}
+	idx.docs[blog.ID] = doc
+}
+
+// remove drops a blog if it is indexed. The caller must hold the write lock.
+func (idx *Index) remove(id uuid.UUID) {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+
+	for term := range doc.freqs {
+		delete(idx.postings[term], id)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	for f := range doc.lengths {
+		idx.totalLengths[f] -= doc.lengths[f]
+	}
+	delete(idx.docs, id)
+}
+
+// Search returns the blogs matching any word of query, best match first,
+// and the total number of matches before Offset and Limit are applied
+func (idx *Index) Search(query string, opts Options) ([]Result, int) {
+	queryTerms := terms(query)
+	if len(queryTerms) == 0 {
+		return []Result{}, 0
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var avgLengths [numFields]float64
+	for f := range avgLengths {
+		avgLengths[f] = 1
+		if len(idx.docs) > 0 && idx.totalLengths[f] > 0 {
+			avgLengths[f] = float64(idx.totalLengths[f]) / float64(len(idx.docs))
+		}
+	}
+
+	now := time.Now()
+	scores := make(map[uuid.UUID]float64)
+	n := float64(len(idx.docs))
+	for _, term := range queryTerms {
+		postings := idx.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+		for id := range postings {
+			doc := idx.docs[id]
+			if !doc.blog.IsLive(now) && !opts.IncludeDrafts {
+				continue
+			}
+
+			// BM25F: length-normalize each field's frequency, weight and sum
+			// them, then saturate the total once
+			freqs := doc.freqs[term]
+			tf := 0.0
+			for f := field(0); f < numFields; f++ {
+				if freqs[f] == 0 {
+					continue
+				}
+				norm := 1 - bm25B + bm25B*float64(doc.lengths[f])/avgLengths[f]
+				tf += fieldWeights[f] * float64(freqs[f]) / norm
+			}
+			scores[id] += idf * tf * (bm25K1 + 1) / (tf + bm25K1)
+		}
+	}
+
+	results := make([]Result, 0, len(scores))
+	for id, score := range scores {
+		results = append(results, Result{Blog: idx.docs[id].blog, Score: score})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if !results[i].Blog.Created.Equal(results[j].Blog.Created) {
+			return results[i].Blog.Created.After(results[j].Blog.Created)
+		}
+		return results[i].Blog.Slug < results[j].Blog.Slug
+	})
+
+	total := len(results)
+	start := min(max(opts.Offset, 0), total)
+	end := total
+	if opts.Limit > 0 && opts.Limit < end-start {
+		end = start + opts.Limit
+	}
+	results = results[start:end]
+
+	// Snippets are only worth building for the page being returned
+	matchTerms := make(map[string]bool, len(queryTerms))
+	for _, term := range queryTerms {
+		matchTerms[term] = true
+	}
+	for i := range results {
+		results[i].Snippet = snippet(idx.docs[results[i].Blog.ID].text, matchTerms)
+	}
+
+	return results, total
+}
@@ -0,0 +1,139 @@
+package search
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"go-react-backend/models"
+
+	"github.com/google/uuid"
+)
+
+// liveBlog returns a published blog with the given slug, title and content
+func liveBlog(slug, title, content string) models.Blog {
+	return models.Blog{
+		ID:        uuid.New(),
+		Slug:      slug,
+		Title:     title,
+		Content:   content,
+		Published: true,
+		Created:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestSearchPaging(t *testing.T) {
+	idx := NewIndex()
+	idx.Load([]models.Blog{
+		liveBlog("a", "Gardening one", ""),
+		liveBlog("b", "Gardening two", ""),
+		liveBlog("c", "Gardening three", ""),
+	})
+
+	tests := []struct {
+		name   string
+		offset int
+		limit  int
+		want   int
+	}{
+		{"everything", 0, 0, 3},
+		{"first page", 0, 2, 2},
+		{"second page", 2, 2, 1},
+		{"past the end", 10, 2, 0},
+		{"negative offset", -5, 2, 2},
+		{"overflowed offset", math.MinInt, 2, 2},
+		{"huge offset", math.MaxInt, 2, 0},
+		{"huge limit", 1, math.MaxInt, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, total := idx.Search("gardening", Options{Offset: tt.offset, Limit: tt.limit})
+			if total != 3 {
+				t.Errorf("total = %d, want 3", total)
+			}
+			if len(results) != tt.want {
+				t.Errorf("got %d results, want %d", len(results), tt.want)
+			}
+		})
+	}
+}
+
+// slugs returns the slugs of results, in order
+func slugs(results []Result) []string {
+	slugs := make([]string, len(results))
+	for i, result := range results {
+		slugs[i] = result.Blog.Slug
+	}
+	return slugs
+}
+
+func TestSearchRanking(t *testing.T) {
+	older := liveBlog("older", "Baking notes", "Today I made sourdough.") // Ties with in-content
+	older.Created = older.Created.Add(-time.Hour)
+	draft := liveBlog("draft", "Sourdough draft", "Sourdough sourdough sourdough.")
+	draft.Published = false
+
+	idx := NewIndex()
+	idx.Load([]models.Blog{
+		liveBlog("in-title", "Sourdough basics", "Flour and water."),
+		liveBlog("in-content", "Baking notes", "Today I made sourdough."),
+		liveBlog("both-terms", "Kitchen diary", "Feeding the sourdough starter every morning."),
+		liveBlog("unrelated", "Gardening", "Tomatoes and basil."),
+		older,
+		draft,
+	})
+
+	tests := []struct {
+		name  string
+		query string
+		opts  Options
+		want  []string
+	}{
+		{
+			name:  "title outweighs content, then shorter content, then newer",
+			query: "sourdough",
+			want:  []string{"in-title", "in-content", "older", "both-terms"},
+		},
+		{
+			name:  "more query terms rank higher",
+			query: "sourdough starter",
+			want:  []string{"both-terms", "in-title", "in-content", "older"},
+		},
+		{
+			name:  "stemmed forms match",
+			query: "bake",
+			want:  []string{"in-content", "older"},
+		},
+		{
+			name:  "stopwords alone match nothing",
+			query: "the and",
+			want:  []string{},
+		},
+		{
+			name:  "drafts only when asked for",
+			query: "sourdough",
+			opts:  Options{IncludeDrafts: true},
+			want:  []string{"draft", "in-title", "in-content", "older", "both-terms"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, total := idx.Search(tt.query, tt.opts)
+			got := slugs(results)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+			if total != len(tt.want) {
+				t.Errorf("total = %d, want %d", total, len(tt.want))
+			}
+			for i := 1; i < len(results); i++ {
+				if results[i].Score > results[i-1].Score {
+					t.Errorf("result %d scores %v, more than the one before it (%v)", i, results[i].Score, results[i-1].Score)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package search
+
+import "testing"
+
+// The examples from each step of Porter's paper, as the whole algorithm
+// leaves them
+func TestStem(t *testing.T) {
+	tests := []struct {
+		step string
+		word string
+		want string
+	}{
+		{"1a", "caresses", "caress"},
+		{"1a", "ponies", "poni"},
+		{"1a", "ties", "ti"},
+		{"1a", "caress", "caress"},
+		{"1a", "cats", "cat"},
+
+		{"1b", "feed", "feed"},
+		{"1b", "agreed", "agre"},
+		{"1b", "plastered", "plaster"},
+		{"1b", "bled", "bled"},
+		{"1b", "motoring", "motor"},
+		{"1b", "sing", "sing"},
+		{"1b", "conflated", "conflat"},
+		{"1b", "troubled", "troubl"},
+		{"1b", "sized", "size"},
+		{"1b", "hopping", "hop"},
+		{"1b", "tanned", "tan"},
+		{"1b", "falling", "fall"},
+		{"1b", "hissing", "hiss"},
+		{"1b", "fizzed", "fizz"},
+		{"1b", "failing", "fail"},
+		{"1b", "filing", "file"},
+
+		{"1c", "happy", "happi"},
+		{"1c", "sky", "sky"},
+
+		{"2", "relational", "relat"},
+		{"2", "conditional", "condit"},
+		{"2", "rational", "ration"},
+		{"2", "valenci", "valenc"},
+		{"2", "hesitanci", "hesit"},
+		{"2", "digitizer", "digit"},
+		{"2", "conformabli", "conform"},
+		{"2", "radicalli", "radic"},
+		{"2", "differentli", "differ"},
+		{"2", "vileli", "vile"},
+		{"2", "analogousli", "analog"},
+		{"2", "vietnamization", "vietnam"},
+		{"2", "predication", "predic"},
+		{"2", "operator", "oper"},
+		{"2", "feudalism", "feudal"},
+		{"2", "decisiveness", "decis"},
+		{"2", "hopefulness", "hope"},
+		{"2", "callousness", "callous"},
+		{"2", "formaliti", "formal"},
+		{"2", "sensitiviti", "sensit"},
+		{"2", "sensibiliti", "sensibl"},
+
+		{"3", "triplicate", "triplic"},
+		{"3", "formative", "form"},
+		{"3", "formalize", "formal"},
+		{"3", "electriciti", "electr"},
+		{"3", "electrical", "electr"},
+		{"3", "hopeful", "hope"},
+		{"3", "goodness", "good"},
+
+		{"4", "revival", "reviv"},
+		{"4", "allowance", "allow"},
+		{"4", "inference", "infer"},
+		{"4", "airliner", "airlin"},
+		{"4", "gyroscopic", "gyroscop"},
+		{"4", "adjustable", "adjust"},
+		{"4", "defensible", "defens"},
+		{"4", "irritant", "irrit"},
+		{"4", "replacement", "replac"},
+		{"4", "adjustment", "adjust"},
+		{"4", "dependent", "depend"},
+		{"4", "adoption", "adopt"},
+		{"4", "homologous", "homolog"},
+		{"4", "communism", "commun"},
+		{"4", "activate", "activ"},
+		{"4", "angulariti", "angular"},
+		{"4", "effective", "effect"},
+		{"4", "bowdlerize", "bowdler"},
+
+		{"5a", "probate", "probat"},
+		{"5a", "rate", "rate"},
+		{"5a", "cease", "ceas"},
+		{"5b", "controll", "control"},
+		{"5b", "roll", "roll"},
+
+		// Left alone: too short, or not plain ASCII letters
+		{"-", "is", "is"},
+		{"-", "naïve", "naïve"},
+		{"-", "mp3s", "mp3s"},
+	}
+
+	for _, tt := range tests {
+		if got := Stem(tt.word); got != tt.want {
+			t.Errorf("step %s: Stem(%q) = %q, want %q", tt.step, tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestStemConflatesVariants(t *testing.T) {
+	for _, word := range []string{"connect", "connected", "connecting", "connection", "connections"} {
+		if got := Stem(word); got != "connect" {
+			t.Errorf("Stem(%q) = %q, want %q", word, got, "connect")
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package search
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// snippetWords is roughly how many indexed words a snippet spans
+const snippetWords = 30
+
+// Markdown syntax removed before indexing, so link targets and formatting
+// characters neither match searches nor show up in snippets
+var (
+	markdownImage    = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	markdownLink     = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	markdownFence    = regexp.MustCompile("(?m)^\\s*(```|~~~).*$")
+	markdownLineMark = regexp.MustCompile(`(?m)^\s*(#{1,6}|>|[-*+]|\d+\.)\s+`)
+	htmlTag          = regexp.MustCompile(`<[^>]+>`)
+	markdownEmphasis = regexp.MustCompile("[*`~]+")
+)
+
+// plainText strips the most common markdown syntax from content and
+// collapses whitespace
+func plainText(content string) string {
+	text := markdownImage.ReplaceAllString(content, "$1")
+	text = markdownLink.ReplaceAllString(text, "$1")
+	text = markdownFence.ReplaceAllString(text, "")
+	text = markdownLineMark.ReplaceAllString(text, "")
+	text = htmlTag.ReplaceAllString(text, " ")
+	text = markdownEmphasis.ReplaceAllString(text, "")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// snippet picks the part of text with the most distinct matching terms and
+// returns it HTML-escaped, with matches wrapped in <mark>. Without matches
+// it returns the beginning of text.
+func snippet(text string, matchTerms map[string]bool) string {
+	tokens := tokenize(text)
+	if len(tokens) == 0 {
+		return html.EscapeString(truncate(text, 200))
+	}
+
+	// Start a little before the first match of the best window so the
+	// snippet has some context
+	best, bestScore := 0, 0
+	for i, tok := range tokens {
+		if !matchTerms[tok.term] {
+			continue
+		}
+		start := i - 5
+		if start < 0 {
+			start = 0
+		}
+		if score := distinctMatches(tokens, start, start+snippetWords, matchTerms); score > bestScore {
+			best, bestScore = start, score
+		}
+	}
+
+	last := best + snippetWords - 1
+	if last >= len(tokens) {
+		last = len(tokens) - 1
+	}
+	from, to := tokens[best].start, tokens[last].end
+	if best == 0 {
+		from = 0
+	}
+	if last == len(tokens)-1 {
+		to = len(text)
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("… ")
+	}
+	pos := from
+	for _, tok := range tokens[best : last+1] {
+		if !matchTerms[tok.term] {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:tok.start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[tok.start:tok.end]))
+		b.WriteString("</mark>")
+		pos = tok.end
+	}
+	b.WriteString(html.EscapeString(text[pos:to]))
+	if to < len(text) {
+		b.WriteString(" …")
+	}
+	return b.String()
+}
+
+// distinctMatches counts the distinct matching terms in tokens[start:end]
+func distinctMatches(tokens []token, start, end int, matchTerms map[string]bool) int {
+	if end > len(tokens) {
+		end = len(tokens)
+	}
+	seen := make(map[string]bool)
+	for _, tok := range tokens[start:end] {
+		if matchTerms[tok.term] {
+			seen[tok.term] = true
+		}
+	}
+	return len(seen)
+}
+
+// truncate shortens text to at most n bytes without splitting a word
+func truncate(text string, n int) string {
+	if len(text) <= n {
+		return text
+	}
+	if cut := strings.LastIndex(text[:n], " "); cut > 0 {
+		n = cut
+	}
+	return text[:n] + " …"
+}
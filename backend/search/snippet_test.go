@@ -0,0 +1,124 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// words returns "wordN" for each N from first to last, space-separated
+func words(first, last int) string {
+	var w []string
+	for i := first; i <= last; i++ {
+		w = append(w, fmt.Sprintf("word%d", i))
+	}
+	return strings.Join(w, " ")
+}
+
+// matching returns the match terms of a query
+func matching(query string) map[string]bool {
+	set := make(map[string]bool)
+	for _, term := range terms(query) {
+		set[term] = true
+	}
+	return set
+}
+
+func TestSnippet(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		want  string
+	}{
+		{
+			name:  "whole short text",
+			text:  "Feeding the sourdough daily",
+			query: "sourdough",
+			want:  "Feeding the <mark>sourdough</mark> daily",
+		},
+		{
+			name:  "stemmed and any case",
+			text:  "Sourdoughs rule",
+			query: "sourdough",
+			want:  "<mark>Sourdoughs</mark> rule",
+		},
+		{
+			name:  "every match in the window",
+			text:  "sourdough starter and more sourdough",
+			query: "sourdough starter",
+			want:  "<mark>sourdough</mark> <mark>starter</mark> and more <mark>sourdough</mark>",
+		},
+		{
+			name:  "escaped around the marks",
+			text:  "Use <b>sourdough</b> & rye",
+			query: "sourdough",
+			want:  "Use &lt;b&gt;<mark>sourdough</mark>&lt;/b&gt; &amp; rye",
+		},
+		{
+			name:  "context before a match in the middle, up to the end",
+			text:  words(1, 20) + " sourdough " + words(21, 40),
+			query: "sourdough",
+			want:  "… " + words(16, 20) + " <mark>sourdough</mark> " + words(21, 40),
+		},
+		{
+			name:  "cut on both sides in a long text",
+			text:  words(1, 20) + " sourdough " + words(21, 100),
+			query: "sourdough",
+			want:  "… " + words(16, 20) + " <mark>sourdough</mark> " + words(21, 44) + " …",
+		},
+		{
+			name:  "window with the most distinct terms",
+			text:  "sourdough " + words(1, 60) + " sourdough starter",
+			query: "sourdough starter",
+			want:  "… " + words(56, 60) + " <mark>sourdough</mark> <mark>starter</mark>",
+		},
+		{
+			name:  "beginning without a match",
+			text:  words(1, 100),
+			query: "sourdough",
+			want:  words(1, 30) + " …",
+		},
+		{
+			name:  "nothing indexable",
+			text:  strings.Repeat("the ", 60),
+			query: "sourdough",
+			want:  strings.TrimSpace(strings.Repeat("the ", 50)) + " …",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snippet(tt.text, matching(tt.query)); got != tt.want {
+				t.Errorf("snippet =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	content := "# Title\n\nSome **bold** and `code` with a [link](http://example.com) and ![alt text](photo.jpg).\n\n" +
+		"> Quoted\n- item <span>one</span>\n1. first\n\n```go\nfmt.Println()\n```\n"
+	want := "Title Some bold and code with a link and alt text. Quoted item one first fmt.Println()"
+	if got := plainText(content); got != want {
+		t.Errorf("plainText =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		text string
+		n    int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"exactly ten", 11, "exactly ten"},
+		{"cut between words", 10, "cut …"},
+		{"unbreakableword", 5, "unbre …"},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.text, tt.n); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.text, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,469 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go-react-backend/models"
+
+	"github.com/google/uuid"
+)
+
+// authDirName is the hidden directory under the data directory that holds
+// user accounts and sessions. It is readable by the server's user only.
+const authDirName = ".auth"
+
+// FileAuthStore implements UserStore, SessionStore and APITokenStore with JSON
+// files in the data directory. It is used whichever blog storage backend is
+// selected.
+type FileAuthStore struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+// NewFileAuthStore creates an auth store under dataDir
+func NewFileAuthStore(dataDir string) (*FileAuthStore, error) {
+	dir := filepath.Join(dataDir, authDirName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create auth directory: %w", err)
+	}
+
+	return &FileAuthStore{dir: dir}, nil
+}
+
+// usersPath returns the file holding every user account
+func (s *FileAuthStore) usersPath() string {
+	return filepath.Join(s.dir, "users.json")
+}
+
+// sessionsPath returns the file holding every active session
+func (s *FileAuthStore) sessionsPath() string {
+	return filepath.Join(s.dir, "sessions.json")
+}
+
+// readJSON loads a JSON file into v, leaving v untouched if the file does not
+// exist yet
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// writeJSON atomically replaces a JSON file with v
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", filepath.Base(path), err)
+	}
+	if err := writeFileAtomic(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// loadUsers loads every user account. The caller must hold the lock.
+func (s *FileAuthStore) loadUsers() ([]models.User, error) {
+	users := []models.User{}
+	if err := readJSON(s.usersPath(), &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// loadSessions loads every session. The caller must hold the lock.
+func (s *FileAuthStore) loadSessions() ([]models.Session, error) {
+	sessions := []models.Session{}
+	if err := readJSON(s.sessionsPath(), &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// GetAllUsers implements the UserStore interface
+func (s *FileAuthStore) GetAllUsers() ([]models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.loadUsers()
+}
+
+// GetUserByID implements the UserStore interface
+func (s *FileAuthStore) GetUserByID(id uuid.UUID) (*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users, err := s.loadUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		if user.ID == id {
+			return &user, nil
+		}
+	}
+
+	return nil, errors.New("user not found")
+}
+
+// GetUserByUsername implements the UserStore interface. Usernames are
+// matched case-insensitively.
+func (s *FileAuthStore) GetUserByUsername(username string) (*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users, err := s.loadUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		if strings.EqualFold(user.Username, username) {
+			return &user, nil
+		}
+	}
+
+	return nil, errors.New("user not found")
+}
+
+// CreateUser implements the UserStore interface
+func (s *FileAuthStore) CreateUser(user models.User) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users, err := s.loadUsers()
+	if err != nil {
+		return models.User{}, err
+	}
+
+	for _, existing := range users {
+		if strings.EqualFold(existing.Username, user.Username) {
+			return models.User{}, errors.New("username already exists")
+		}
+	}
+
+	user.ID = uuid.New()
+	user.Created = time.Now()
+
+	if err := writeJSON(s.usersPath(), append(users, user)); err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// CreateSession implements the SessionStore interface
+func (s *FileAuthStore) CreateSession(session models.Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions, err := s.loadSessions()
+	if err != nil {
+		return err
+	}
+
+	return writeJSON(s.sessionsPath(), append(sessions, session))
+}
+
+// GetSession implements the SessionStore interface
+func (s *FileAuthStore) GetSession(tokenHash string) (*models.Session, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sessions, err := s.loadSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		if session.TokenHash == tokenHash {
+			return &session, nil
+		}
+	}
+
+	return nil, errors.New("session not found")
+}
+
+// DeleteSession implements the SessionStore interface
+func (s *FileAuthStore) DeleteSession(tokenHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions, err := s.loadSessions()
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]models.Session, 0, len(sessions))
+	for _, session := range sessions {
+		if session.TokenHash != tokenHash {
+			remaining = append(remaining, session)
+		}
+	}
+	if len(remaining) == len(sessions) {
+		return nil
+	}
+
+	return writeJSON(s.sessionsPath(), remaining)
+}
+
+// DeleteExpiredSessions implements the SessionStore interface
+func (s *FileAuthStore) DeleteExpiredSessions(now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions, err := s.loadSessions()
+	if err != nil {
+		return 0, err
+	}
+
+	remaining := make([]models.Session, 0, len(sessions))
+	for _, session := range sessions {
+		if session.Expires.After(now) {
+			remaining = append(remaining, session)
+		}
+	}
+
+	expired := len(sessions) - len(remaining)
+	if expired == 0 {
+		return 0, nil
+	}
+
+	return expired, writeJSON(s.sessionsPath(), remaining)
+}
+
+// tokensPath returns the file holding every API token
+func (s *FileAuthStore) tokensPath() string {
+	return filepath.Join(s.dir, "tokens.json")
+}
+
+// loadAPITokens loads every API token. The caller must hold the lock.
+func (s *FileAuthStore) loadAPITokens() ([]models.APIToken, error) {
+	tokens := []models.APIToken{}
+	if err := readJSON(s.tokensPath(), &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// GetAPITokensByUser implements the APITokenStore interface
+func (s *FileAuthStore) GetAPITokensByUser(userID uuid.UUID) ([]models.APIToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tokens, err := s.loadAPITokens()
+	if err != nil {
+		return nil, err
+	}
+
+	userTokens := []models.APIToken{}
+	for _, token := range tokens {
+		if token.UserID == userID {
+			userTokens = append(userTokens, token)
+		}
+	}
+	return userTokens, nil
+}
+
+// GetAPITokenByHash implements the APITokenStore interface
+func (s *FileAuthStore) GetAPITokenByHash(tokenHash string) (*models.APIToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tokens, err := s.loadAPITokens()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, token := range tokens {
+		if token.TokenHash == tokenHash {
+			return &token, nil
+		}
+	}
+
+	return nil, errors.New("token not found")
+}
+
+// CreateAPIToken implements the APITokenStore interface
+func (s *FileAuthStore) CreateAPIToken(token models.APIToken) (models.APIToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.loadAPITokens()
+	if err != nil {
+		return models.APIToken{}, err
+	}
+
+	token.ID = uuid.New()
+	token.Created = time.Now()
+
+	if err := writeJSON(s.tokensPath(), append(tokens, token)); err != nil {
+		return models.APIToken{}, err
+	}
+
+	return token, nil
+}
+
+// DeleteAPIToken implements the APITokenStore interface. Only the owner's
+// tokens can be deleted.
+func (s *FileAuthStore) DeleteAPIToken(userID uuid.UUID, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.loadAPITokens()
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]models.APIToken, 0, len(tokens))
+	for _, token := range tokens {
+		if token.ID != id || token.UserID != userID {
+			remaining = append(remaining, token)
+		}
+	}
+	if len(remaining) == len(tokens) {
+		return errors.New("token not found")
+	}
+
+	return writeJSON(s.tokensPath(), remaining)
+}
+
+// TouchAPIToken implements the APITokenStore interface
+func (s *FileAuthStore) TouchAPIToken(id uuid.UUID, usedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.loadAPITokens()
+	if err != nil {
+		return err
+	}
+
+	for i := range tokens {
+		if tokens[i].ID == id {
+			tokens[i].LastUsed = &usedAt
+			return writeJSON(s.tokensPath(), tokens)
+		}
+	}
+
+	return errors.New("token not found")
+}
+
+// UpdateUser implements the UserStore interface, replacing the stored user
+// with the same ID
+func (s *FileAuthStore) UpdateUser(user models.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users, err := s.loadUsers()
+	if err != nil {
+		return err
+	}
+
+	for i := range users {
+		if users[i].ID == user.ID {
+			users[i] = user
+			return writeJSON(s.usersPath(), users)
+		}
+	}
+
+	return errors.New("user not found")
+}
+
+// DeleteUser implements the UserStore interface. The user's sessions and API
+// tokens are removed with it.
+func (s *FileAuthStore) DeleteUser(id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users, err := s.loadUsers()
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]models.User, 0, len(users))
+	for _, user := range users {
+		if user.ID != id {
+			remaining = append(remaining, user)
+		}
+	}
+	if len(remaining) == len(users) {
+		return errors.New("user not found")
+	}
+	if err := writeJSON(s.usersPath(), remaining); err != nil {
+		return err
+	}
+
+	sessions, err := s.loadSessions()
+	if err != nil {
+		return err
+	}
+	remainingSessions := make([]models.Session, 0, len(sessions))
+	for _, session := range sessions {
+		if session.UserID != id {
+			remainingSessions = append(remainingSessions, session)
+		}
+	}
+	if err := writeJSON(s.sessionsPath(), remainingSessions); err != nil {
+		return err
+	}
+
+	tokens, err := s.loadAPITokens()
+	if err != nil {
+		return err
+	}
+	remainingTokens := make([]models.APIToken, 0, len(tokens))
+	for _, token := range tokens {
+		if token.UserID != id {
+			remainingTokens = append(remainingTokens, token)
+		}
+	}
+	return writeJSON(s.tokensPath(), remainingTokens)
+}
+
+// previewSecretPath returns the file holding the preview token signing secret
+func (s *FileAuthStore) previewSecretPath() string {
+	return filepath.Join(s.dir, "preview_secret")
+}
+
+// PreviewSecret returns the secret used to sign preview tokens, generating
+// and saving a random one the first time so tokens survive restarts
+func (s *FileAuthStore) PreviewSecret() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.previewSecretPath())
+	if err == nil {
+		secret, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse preview_secret: %w", err)
+		}
+		return secret, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read preview_secret: %w", err)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate preview secret: %w", err)
+	}
+	if err := writeFileAtomic(s.previewSecretPath(), []byte(hex.EncodeToString(secret)+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write preview_secret: %w", err)
+	}
+	return secret, nil
+}
@@ -32,11 +32,16 @@ func NewFileBlogStore(dataDir string) (*FileBlogStore, error) {
 		dataDir: dataDir,
 	}
 
+	// Clean up after any write that was interrupted by a crash
+	if err := store.recover(); err != nil {
+		return nil, fmt.Errorf("failed to recover data directory: %w", err)
+	}
+
 	return store, nil
 }
 
 // slugify converts a title to a URL-friendly slug
-func (s *FileBlogStore) slugify(title string) string {
+func slugify(title string) string {
 	// Convert to lowercase and replace spaces with hyphens
 	slug := strings.ToLower(title)
 	slug = strings.ReplaceAll(slug, " ", "-")
@@ -83,8 +88,30 @@ func (s *FileBlogStore) generateUUID() uuid.UUID {
 	return uuid.New()
 }
 
-// saveBlog saves a blog to its directory
-func (s *FileBlogStore) saveBlog(blog models.Blog, slug string) error {
+// applyBlogDefaults fills in the metadata fields a new blog needs when the
+// client did not provide them
+func applyBlogDefaults(blog *models.Blog) {
+	if blog.AuthorName == "" {
+		blog.AuthorName = "John Doe"
+	}
+	if blog.AuthorUsername == "" {
+		blog.AuthorUsername = "johndoe"
+	}
+	if blog.MetaName == "" {
+		blog.MetaName = blog.Title
+	}
+	if blog.MetaDescription == "" {
+		blog.MetaDescription = fmt.Sprintf("Read about %s", blog.Title)
+	}
+	if blog.Slug == "" {
+		blog.Slug = slugify(blog.Title)
+	}
+	blog.Tags = models.NormalizeTags(blog.Tags)
+}
+
+// saveBlog saves a blog to its directory as part of txn, which restores
+// both files if either can't be written
+func (s *FileBlogStore) saveBlog(txn *blogTxn, blog models.Blog, slug string) error {
 	blogDir := s.GetBlogDir(slug)
 	
 	// Create blog directory
@@ -98,13 +125,21 @@ func (s *FileBlogStore) saveBlog(blog models.Blog, slug string) error {
 		"slug":             slug,
 		"title":            blog.Title,
 		"image":            blog.Image,
+		"image_variants":   imageVariantsOrEmpty(blog.ImageVariants),
+		"image_thumbnail":  blog.ImageThumbnail,
+		"image_mime_type":  blog.ImageMimeType,
+		"media":            mediaOrEmpty(blog.Media),
 		"author_name":      blog.AuthorName,
 		"author_username":  blog.AuthorUsername,
 		"meta_name":        blog.MetaName,
 		"meta_description": blog.MetaDescription,
+		"tags":             models.NormalizeTags(blog.Tags),
+		"category":         blog.Category,
 		"created":          blog.Created.Format(time.RFC3339),
 		"updated":          blog.Updated.Format(time.RFC3339),
 		"published":        blog.Published,
+		"publish_at":       metadataTime(blog.PublishAt),
+		"unpublish_at":     metadataTime(blog.UnpublishAt),
 	}
 
 	// Save metadata
@@ -113,15 +148,15 @@ func (s *FileBlogStore) saveBlog(blog models.Blog, slug string) error {
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
-	metadataPath := s.getBlogMetadataPath(slug)
-	if err := os.WriteFile(metadataPath, metadataData, 0644); err != nil {
-		return fmt.Errorf("failed to write metadata: %w", err)
-	}
-
-	// Save content
+	// Save content first: a directory only counts as a blog once its
+	// metadata exists
 	contentPath := s.getBlogContentPath(slug)
-	if err := os.WriteFile(contentPath, []byte(blog.Content), 0644); err != nil {
-		return fmt.Errorf("failed to write content: %w", err)
+	metadataPath := s.getBlogMetadataPath(slug)
+	if err := txn.writeFiles([]string{contentPath, metadataPath}, map[string][]byte{
+		contentPath:  []byte(blog.Content),
+		metadataPath: metadataData,
+	}); err != nil {
+		return fmt.Errorf("failed to write blog files: %w", err)
 	}
 
 	return nil
@@ -139,7 +174,7 @@ func (s *FileBlogStore) saveBlogImage(slug string, imageFilename string, imageDa
 
 	// Save image file
 	imagePath := s.getBlogImagePath(slug, imageFilename)
-	if err := os.WriteFile(imagePath, imageData, 0644); err != nil {
+	if err := writeFileAtomic(imagePath, imageData, 0644); err != nil {
 		fmt.Printf("❌ Failed to write image to %s: %v\n", imagePath, err)
 		return fmt.Errorf("failed to write image: %w", err)
 	}
@@ -161,6 +196,262 @@ func (s *FileBlogStore) deleteBlogImage(slug string, imageFilename string) error
 	return nil
 }
 
+// loadBlogDir loads a single blog from its directory. Metadata may have been
+// edited by hand, so missing or mistyped fields are reported as errors.
+func loadBlogDir(blogDir string) (models.Blog, error) {
+	// Check if it's a blog directory (has content.md and metadata.json)
+	contentPath := filepath.Join(blogDir, "content.md")
+	metadataPath := filepath.Join(blogDir, "metadata.json")
+
+	// Load metadata
+	metadataData, err := os.ReadFile(metadataPath)
+	if err != nil {
+		return models.Blog{}, err
+	}
+
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(metadataData, &metadata); err != nil {
+		return models.Blog{}, err
+	}
+
+	// Load content
+	content, err := os.ReadFile(contentPath)
+	if err != nil {
+		return models.Blog{}, err
+	}
+
+	// Parse timestamps from metadata
+	var created, updated time.Time
+	if createdStr, ok := metadata["created"].(string); ok {
+		if parsed, err := time.Parse(time.RFC3339, createdStr); err == nil {
+			created = parsed
+		} else {
+			// Try parsing with a more flexible format
+			if parsed, err := time.Parse("2006-01-02T15:04:05-07:00", createdStr); err == nil {
+				created = parsed
+			} else {
+				created = time.Now() // Fallback to current time
+			}
+		}
+	} else {
+		created = time.Now() // Fallback to current time
+	}
+
+	if updatedStr, ok := metadata["updated"].(string); ok {
+		if parsed, err := time.Parse(time.RFC3339, updatedStr); err == nil {
+			updated = parsed
+		} else {
+			// Try parsing with a more flexible format
+			if parsed, err := time.Parse("2006-01-02T15:04:05-07:00", updatedStr); err == nil {
+				updated = parsed
+			} else {
+				updated = time.Now() // Fallback to current time
+			}
+		}
+	} else {
+		updated = time.Now() // Fallback to current time
+	}
+
+	// Parse UUID from metadata
+	idStr, ok := metadata["id"].(string)
+	if !ok {
+		return models.Blog{}, errors.New("blog metadata has no id")
+	}
+	blogID, err := uuid.Parse(idStr)
+	if err != nil {
+		return models.Blog{}, err
+	}
+
+	title, err := metadataString(metadata, "title")
+	if err != nil {
+		return models.Blog{}, err
+	}
+	if title == "" {
+		return models.Blog{}, errors.New("blog metadata has no title")
+	}
+
+	// Other text fields may be missing or null, e.g. image in older blogs
+	var image, authorName, authorUsername, metaName, metaDescription, slug string
+	for _, field := range []struct {
+		key   string
+		value *string
+	}{
+		{"image", &image},
+		{"author_name", &authorName},
+		{"author_username", &authorUsername},
+		{"meta_name", &metaName},
+		{"meta_description", &metaDescription},
+		{"slug", &slug},
+	} {
+		if *field.value, err = metadataString(metadata, field.key); err != nil {
+			return models.Blog{}, err
+		}
+	}
+
+	published := false
+	if value, ok := metadata["published"]; ok && value != nil {
+		if published, ok = value.(bool); !ok {
+			return models.Blog{}, errors.New("published must be true or false")
+		}
+	}
+
+	// Tags and category are missing from blogs saved before they existed
+	tags := []string{}
+	if tagValues, ok := metadata["tags"].([]interface{}); ok {
+		for _, tagValue := range tagValues {
+			if tag, ok := tagValue.(string); ok {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	category, _ := metadata["category"].(string)
+
+	// Image variants are missing from blogs saved before they existed
+	imageVariants, err := parseMetadataImageVariants(metadata["image_variants"])
+	if err != nil {
+		return models.Blog{}, fmt.Errorf("invalid image_variants: %w", err)
+	}
+	imageThumbnail, _ := metadata["image_thumbnail"].(string)
+	imageMimeType, _ := metadata["image_mime_type"].(string)
+
+	// Media is missing from blogs saved before collections existed
+	media, err := parseMetadataMedia(metadata["media"])
+	if err != nil {
+		return models.Blog{}, fmt.Errorf("invalid media: %w", err)
+	}
+
+	// Schedules are null or missing when unset
+	publishAt, err := parseMetadataTime(metadata["publish_at"])
+	if err != nil {
+		return models.Blog{}, fmt.Errorf("invalid publish_at: %w", err)
+	}
+	unpublishAt, err := parseMetadataTime(metadata["unpublish_at"])
+	if err != nil {
+		return models.Blog{}, fmt.Errorf("invalid unpublish_at: %w", err)
+	}
+
+	// Create blog model with metadata
+	blog := models.Blog{
+		ID:              blogID,
+		Title:           title,
+		Content:         string(content),
+		Image:           image,
+		ImageVariants:   imageVariants,
+		ImageThumbnail:  imageThumbnail,
+		ImageMimeType:   imageMimeType,
+		Media:           media,
+		AuthorName:      authorName,
+		AuthorUsername:  authorUsername,
+		MetaName:        metaName,
+		MetaDescription: metaDescription,
+		Tags:            tags,
+		Category:        category,
+		Slug:            slug,
+		Created:         created,
+		Updated:         updated,
+		Published:       published,
+		PublishAt:       publishAt,
+		UnpublishAt:     unpublishAt,
+	}
+
+	return blog, nil
+}
+
+// metadataString reads an optional text field of blog metadata, returning ""
+// when it is missing or null
+func metadataString(metadata map[string]interface{}, key string) (string, error) {
+	value, ok := metadata[key]
+	if !ok || value == nil {
+		return "", nil
+	}
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%s must be a string", key)
+	}
+	return str, nil
+}
+
+// metadataTime formats an optional time for blog metadata
+func metadataTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Format(time.RFC3339)
+}
+
+// parseMetadataTime parses an optional time written by metadataTime
+func parseMetadataTime(value interface{}) (*time.Time, error) {
+	str, ok := value.(string)
+	if !ok || str == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, str)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// imageVariantsOrEmpty keeps blogs without variants from being stored as null
+func imageVariantsOrEmpty(variants []models.ImageVariant) []models.ImageVariant {
+	if variants == nil {
+		return []models.ImageVariant{}
+	}
+	return variants
+}
+
+// parseMetadataImageVariants reads the image variants written by saveBlog
+func parseMetadataImageVariants(value interface{}) ([]models.ImageVariant, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var variants []models.ImageVariant
+	if err := json.Unmarshal(data, &variants); err != nil {
+		return nil, err
+	}
+	return variants, nil
+}
+
+// mediaOrEmpty keeps blogs without media from being stored as null
+func mediaOrEmpty(media []models.MediaItem) []models.MediaItem {
+	if media == nil {
+		return []models.MediaItem{}
+	}
+	return media
+}
+
+// parseMetadataMedia reads the media collection written by saveBlog
+func parseMetadataMedia(value interface{}) ([]models.MediaItem, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var media []models.MediaItem
+	if err := json.Unmarshal(data, &media); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// replacedImageFiles returns the image files of before that after no longer
+// uses
+func replacedImageFiles(before models.Blog, after models.Blog) []string {
+	var replaced []string
+	for _, filename := range before.ImageFiles() {
+		if !after.HasImageFile(filename) {
+			replaced = append(replaced, filename)
+		}
+	}
+	return replaced
+}
+
 // loadAllBlogs loads all blogs from the directory structure
 func (s *FileBlogStore) loadAllBlogs() ([]models.Blog, error) {
 	entries, err := os.ReadDir(s.dataDir)
@@ -170,101 +461,16 @@ func (s *FileBlogStore) loadAllBlogs() ([]models.Blog, error) {
 
 	var blogs []models.Blog
 	for _, entry := range entries {
-		if entry.IsDir() {
-			// Check if it's a blog directory (has content.md and metadata.json)
-			contentPath := filepath.Join(s.dataDir, entry.Name(), "content.md")
-			metadataPath := filepath.Join(s.dataDir, entry.Name(), "metadata.json")
-			
-			if _, err := os.Stat(contentPath); err == nil {
-				if _, err := os.Stat(metadataPath); err == nil {
-					// Load metadata
-					metadataData, err := os.ReadFile(metadataPath)
-					if err != nil {
-						continue // Skip this blog if metadata can't be read
-					}
-
-					var metadata map[string]interface{}
-					if err := json.Unmarshal(metadataData, &metadata); err != nil {
-						continue // Skip this blog if metadata can't be parsed
-					}
-
-					// Load content
-					content, err := os.ReadFile(contentPath)
-					if err != nil {
-						continue // Skip this blog if content can't be read
-					}
-
-					// Parse timestamps from metadata
-					var created, updated time.Time
-					if createdStr, ok := metadata["created"].(string); ok {
-						if parsed, err := time.Parse(time.RFC3339, createdStr); err == nil {
-							created = parsed
-						} else {
-							// Try parsing with a more flexible format
-							if parsed, err := time.Parse("2006-01-02T15:04:05-07:00", createdStr); err == nil {
-								created = parsed
-							} else {
-								created = time.Now() // Fallback to current time
-							}
-						}
-					} else {
-						created = time.Now() // Fallback to current time
-					}
-					
-					if updatedStr, ok := metadata["updated"].(string); ok {
-						if parsed, err := time.Parse(time.RFC3339, updatedStr); err == nil {
-							updated = parsed
-						} else {
-							// Try parsing with a more flexible format
-							if parsed, err := time.Parse("2006-01-02T15:04:05-07:00", updatedStr); err == nil {
-								updated = parsed
-							} else {
-								updated = time.Now() // Fallback to current time
-							}
-						}
-					} else {
-						updated = time.Now() // Fallback to current time
-					}
-
-					// Parse UUID from metadata
-					var blogID uuid.UUID
-					if idStr, ok := metadata["id"].(string); ok {
-						if parsed, err := uuid.Parse(idStr); err == nil {
-							blogID = parsed
-						} else {
-							// Skip this blog if UUID can't be parsed
-							continue
-						}
-					} else {
-						// Skip this blog if ID is not a string
-						continue
-					}
-
-					// Get image field (handle both old and new format)
-					var image string
-					if imageVal, ok := metadata["image"]; ok && imageVal != nil {
-						image = imageVal.(string)
-					}
-
-					// Create blog model with metadata
-					blog := models.Blog{
-						ID:              blogID,
-						Title:           metadata["title"].(string),
-						Content:         string(content),
-						Image:           image,
-						AuthorName:      metadata["author_name"].(string),
-						AuthorUsername:  metadata["author_username"].(string),
-						MetaName:        metadata["meta_name"].(string),
-						MetaDescription: metadata["meta_description"].(string),
-						Slug:            metadata["slug"].(string),
-						Created:         created,
-						Updated:         updated,
-						Published:       metadata["published"].(bool),
-					}
-
-					blogs = append(blogs, blog)
-				}
+		// Hidden directories (e.g. the trash) never hold live blogs
+		if entry.IsDir() && !isHiddenName(entry.Name()) {
+			blog, err := loadBlogDir(filepath.Join(s.dataDir, entry.Name()))
+			if err != nil {
+				continue // Skip directories that aren't valid blogs
 			}
+			// The directory name is the slug, even if the directory was
+			// renamed by hand and metadata.json still has the old one
+			blog.Slug = entry.Name()
+			blogs = append(blogs, blog)
 		}
 	}
 
@@ -303,6 +509,24 @@ func (s *FileBlogStore) GetBlogBySlug(slug string) (*models.Blog, error) {
 	return nil, errors.New("blog not found")
 }
 
+func (s *FileBlogStore) GetBlogByID(id uuid.UUID) (*models.Blog, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	blogs, err := s.loadAllBlogs()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, blog := range blogs {
+		if blog.ID == id {
+			return &blog, nil
+		}
+	}
+
+	return nil, errors.New("blog not found")
+}
+
 func (s *FileBlogStore) CreateBlog(blog models.Blog) (models.Blog, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -315,24 +539,32 @@ func (s *FileBlogStore) CreateBlog(blog models.Blog) (models.Blog, error) {
 	blog.Updated = now
 
 	// Set default metadata if not provided
-	if blog.AuthorName == "" {
-		blog.AuthorName = "John Doe"
-	}
-	if blog.AuthorUsername == "" {
-		blog.AuthorUsername = "johndoe"
-	}
-	if blog.MetaName == "" {
-		blog.MetaName = blog.Title
+	applyBlogDefaults(&blog)
+
+	// Never write over another blog's directory, even one that doesn't load
+	blogs, err := s.loadAllBlogs()
+	if err != nil {
+		return models.Blog{}, err
 	}
-	if blog.MetaDescription == "" {
-		blog.MetaDescription = fmt.Sprintf("Read about %s", blog.Title)
+	for _, existing := range blogs {
+		if existing.Slug == blog.Slug {
+			return models.Blog{}, models.ErrSlugExists
+		}
 	}
-	if blog.Slug == "" {
-		blog.Slug = s.slugify(blog.Title)
+	if _, err := os.Stat(s.GetBlogDir(blog.Slug)); err == nil {
+		return models.Blog{}, models.ErrSlugExists
 	}
 
 	// Save blog in directory structure
-	if err := s.saveBlog(blog, blog.Slug); err != nil {
+	txn, err := s.beginBlogTxn(blog.ID.String(), blog.Slug, blog.Slug)
+	if err != nil {
+		return models.Blog{}, err
+	}
+	if err := s.saveBlog(txn, blog, blog.Slug); err != nil {
+		txn.rollback()
+		return models.Blog{}, err
+	}
+	if err := txn.commit(); err != nil {
 		return models.Blog{}, err
 	}
 
@@ -344,7 +576,12 @@ func (s *FileBlogStore) CreateBlog(blog models.Blog) (models.Blog, error) {
 func (s *FileBlogStore) UpdateBlogBySlug(slug string, updates models.UpdateBlogRequest) (*models.Blog, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.updateBlogBySlug(slug, updates)
+}
 
+// updateBlogBySlug applies updates to a blog and records the previous
+// version as a revision. The caller must hold the write lock.
+func (s *FileBlogStore) updateBlogBySlug(slug string, updates models.UpdateBlogRequest) (*models.Blog, error) {
 	// Load all blogs and find by slug
 	blogs, err := s.loadAllBlogs()
 	if err != nil {
@@ -367,12 +604,14 @@ func (s *FileBlogStore) UpdateBlogBySlug(slug string, updates models.UpdateBlogR
 	if updates.Slug != nil && *updates.Slug != existingBlog.Slug {
 		for _, blog := range blogs {
 			if blog.Slug == *updates.Slug && blog.ID != existingBlog.ID {
-				return nil, errors.New("slug already exists")
+				return nil, models.ErrSlugExists
 			}
 		}
 	}
 
-	// Store old slug for folder renaming
+	// Keep the previous version for the revision log, folder renaming and
+	// image cleanup
+	previous := *existingBlog
 	oldSlug := existingBlog.Slug
 
 	// Apply updates
@@ -383,12 +622,20 @@ func (s *FileBlogStore) UpdateBlogBySlug(slug string, updates models.UpdateBlogR
 		existingBlog.Content = *updates.Content
 	}
 	if updates.Image != nil {
-		// Delete old image if it exists
-		if existingBlog.Image != "" {
-			s.deleteBlogImage(oldSlug, existingBlog.Image)
-		}
 		existingBlog.Image = *updates.Image
 	}
+	if updates.ImageVariants != nil {
+		existingBlog.ImageVariants = *updates.ImageVariants
+	}
+	if updates.ImageThumbnail != nil {
+		existingBlog.ImageThumbnail = *updates.ImageThumbnail
+	}
+	if updates.ImageMimeType != nil {
+		existingBlog.ImageMimeType = *updates.ImageMimeType
+	}
+	if updates.Media != nil {
+		existingBlog.Media = *updates.Media
+	}
 	if updates.AuthorName != nil {
 		existingBlog.AuthorName = *updates.AuthorName
 	}
@@ -401,30 +648,88 @@ func (s *FileBlogStore) UpdateBlogBySlug(slug string, updates models.UpdateBlogR
 	if updates.MetaDescription != nil {
 		existingBlog.MetaDescription = *updates.MetaDescription
 	}
+	if updates.Tags != nil {
+		existingBlog.Tags = models.NormalizeTags(*updates.Tags)
+	}
+	if updates.Category != nil {
+		existingBlog.Category = *updates.Category
+	}
 	if updates.Slug != nil {
 		existingBlog.Slug = *updates.Slug
 	}
 	if updates.Published != nil {
 		existingBlog.Published = *updates.Published
 	}
+	models.ApplySchedule(&existingBlog.PublishAt, updates.PublishAt)
+	models.ApplySchedule(&existingBlog.UnpublishAt, updates.UnpublishAt)
 	existingBlog.Updated = time.Now()
 
-	// If slug changed, rename the folder
-	if updates.Slug != nil && *updates.Slug != oldSlug {
-		oldDir := s.GetBlogDir(oldSlug)
-		newDir := s.GetBlogDir(*updates.Slug)
-		
-		// Rename the directory
-		if err := os.Rename(oldDir, newDir); err != nil {
-			return nil, fmt.Errorf("failed to rename blog directory: %w", err)
+	// Rename the folder if the slug changed and write the new files as one
+	// transaction: a failed write restores both files and moves the folder
+	// back to its old slug
+	txn, err := s.beginBlogTxn(existingBlog.ID.String(), oldSlug, existingBlog.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	// Record what the blog looked like before this update
+	var revisionPath string
+	if changed := models.ChangedFields(previous, *existingBlog); len(changed) > 0 {
+		revisionPath, err = s.saveRevision(existingBlog.Slug, models.Revision{
+			BlogID:        existingBlog.ID,
+			Timestamp:     existingBlog.Updated,
+			ChangedFields: changed,
+			Previous:      previous,
+		})
+		if err != nil {
+			txn.rollback()
+			return nil, err
+		}
+	}
+
+	// Remember the old slug so links to it can be redirected
+	var slugHistory []models.SlugChange
+	if oldSlug != existingBlog.Slug {
+		slugHistory, err = s.loadSlugHistory(existingBlog.Slug)
+		if err == nil {
+			err = s.saveSlugHistory(existingBlog.Slug, appendSlugChange(slugHistory, models.SlugChange{
+				Slug:      oldSlug,
+				ChangedAt: existingBlog.Updated,
+			}))
+		}
+		if err != nil {
+			if revisionPath != "" {
+				os.Remove(revisionPath)
+			}
+			txn.rollback()
+			return nil, err
 		}
 	}
 
 	// Save updated blog
-	if err := s.saveBlog(*existingBlog, slug); err != nil {
+	if err := s.saveBlog(txn, *existingBlog, existingBlog.Slug); err != nil {
+		if revisionPath != "" {
+			os.Remove(revisionPath)
+		}
+		if oldSlug != existingBlog.Slug {
+			s.saveSlugHistory(existingBlog.Slug, slugHistory)
+		}
+		txn.rollback()
 		return nil, err
 	}
 
+	if err := txn.commit(); err != nil {
+		return nil, err
+	}
+
+	// Delete the replaced image files only once the update is durable.
+	// Files with the same filename are overwritten by the caller instead.
+	for _, oldImage := range replacedImageFiles(previous, *existingBlog) {
+		if err := s.deleteBlogImage(existingBlog.Slug, oldImage); err != nil {
+			fmt.Printf("Warning: Failed to delete old image for blog %s: %v\n", existingBlog.Slug, err)
+		}
+	}
+
 	return existingBlog, nil
 }
 
@@ -438,29 +743,41 @@ func (s *FileBlogStore) DeleteBlogBySlug(slug string) error {
 		return err
 	}
 
-	// Check if blog exists
-	blogExists := false
+	// Find the blog to delete
+	var existingBlog *models.Blog
 	for _, blog := range blogs {
 		if blog.Slug == slug {
-			blogExists = true
+			existingBlog = &blog
 			break
 		}
 	}
 
-	if !blogExists {
+	if existingBlog == nil {
 		return errors.New("blog not found")
 	}
 
-	// Remove entire blog directory
-	blogDir := s.GetBlogDir(slug)
-	if err := os.RemoveAll(blogDir); err != nil {
-		return fmt.Errorf("failed to delete blog directory: %w", err)
-	}
-
-	return nil
+	// Move the entire blog directory to the trash; it is only removed for
+	// good once the trash is purged
+	return s.moveToTrash(*existingBlog)
 }
 
 // SaveBlogImage implements the BlogStore interface
 func (s *FileBlogStore) SaveBlogImage(slug string, imageFilename string, imageData []byte) error {
 	return s.saveBlogImage(slug, imageFilename, imageData)
-}
\ No newline at end of file
+}
+
+// GetBlogImage implements the BlogStore interface
+func (s *FileBlogStore) GetBlogImage(slug string, imageFilename string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	imageData, err := os.ReadFile(s.getBlogImagePath(slug, imageFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.New("image not found")
+		}
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	return imageData, nil
+}
@@ -0,0 +1,374 @@
+package storage
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go-react-backend/models"
+
+	"github.com/google/uuid"
+)
+
+// blogIndex is an immutable snapshot of every blog in the wrapped store
+type blogIndex struct {
+	blogs  []models.Blog // in the order returned by the wrapped store
+	bySlug map[string]int
+	byID   map[uuid.UUID]int
+}
+
+// CachedBlogStore decorates any BlogStore with an in-memory index of all
+// blogs. Reads are served from memory; writes go to the wrapped store and
+// drop the index so the next read reloads it.
+type CachedBlogStore struct {
+	store  models.BlogStore
+	mu     sync.RWMutex
+	index  *blogIndex // nil until loaded or after invalidation
+	events *EventBus  // receives a ChangeEvent per successful write, if set
+}
+
+// NewCachedBlogStore wraps store with an in-memory read cache
+func NewCachedBlogStore(store models.BlogStore) *CachedBlogStore {
+	return &CachedBlogStore{store: store}
+}
+
+// PublishChanges makes every successful write through the store publish a
+// ChangeEvent to events, so subsystems like search see changes made through
+// the API with any backend. Call it before the store is shared.
+func (c *CachedBlogStore) PublishChanges(events *EventBus) {
+	c.events = events
+}
+
+// publish sends event to the configured event bus, if any. It must be called
+// without the lock held, as subscribers may read from the cache.
+func (c *CachedBlogStore) publish(event ChangeEvent) {
+	if c.events != nil {
+		c.events.Publish(event)
+	}
+}
+
+// publishUpdate publishes the change made to a blog that had slug before
+func (c *CachedBlogStore) publishUpdate(slug string, blog *models.Blog) {
+	if blog.Slug != slug {
+		c.publish(ChangeEvent{Type: BlogRenamed, ID: blog.ID, Slug: blog.Slug, OldSlug: slug})
+	} else {
+		c.publish(ChangeEvent{Type: BlogUpdated, ID: blog.ID, Slug: blog.Slug})
+	}
+}
+
+// write runs fn with the write lock held and drops the index once it returns
+func (c *CachedBlogStore) write(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer func() { c.index = nil }()
+	fn()
+}
+
+// Invalidate drops the cached index, forcing the next read to reload it from
+// the wrapped store. Use it when the underlying data changes behind the
+// cache's back (e.g. files edited on disk).
+func (c *CachedBlogStore) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.index = nil
+}
+
+// loadIndex returns the current index, loading it from the wrapped store if
+// needed
+func (c *CachedBlogStore) loadIndex() (*blogIndex, error) {
+	c.mu.RLock()
+	index := c.index
+	c.mu.RUnlock()
+	if index != nil {
+		return index, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Another goroutine may have loaded it while we waited for the lock
+	if c.index != nil {
+		return c.index, nil
+	}
+
+	blogs, err := c.store.GetAllBlogs()
+	if err != nil {
+		return nil, err
+	}
+
+	index = &blogIndex{
+		blogs:  blogs,
+		bySlug: make(map[string]int, len(blogs)),
+		byID:   make(map[uuid.UUID]int, len(blogs)),
+	}
+	for i, blog := range blogs {
+		index.bySlug[blog.Slug] = i
+		index.byID[blog.ID] = i
+	}
+
+	c.index = index
+	return index, nil
+}
+
+// Interface implementation methods
+func (c *CachedBlogStore) GetAllBlogs() ([]models.Blog, error) {
+	index, err := c.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	// Hand out a copy so callers can't reorder or modify the cached slice
+	blogs := make([]models.Blog, len(index.blogs))
+	copy(blogs, index.blogs)
+	return blogs, nil
+}
+
+func (c *CachedBlogStore) GetBlogBySlug(slug string) (*models.Blog, error) {
+	index, err := c.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	i, ok := index.bySlug[slug]
+	if !ok {
+		return nil, errors.New("blog not found")
+	}
+
+	blog := index.blogs[i]
+	return &blog, nil
+}
+
+// GetBlogByID implements the BlogStore interface using the in-memory index
+func (c *CachedBlogStore) GetBlogByID(id uuid.UUID) (*models.Blog, error) {
+	index, err := c.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	i, ok := index.byID[id]
+	if !ok {
+		return nil, errors.New("blog not found")
+	}
+
+	blog := index.blogs[i]
+	return &blog, nil
+}
+
+func (c *CachedBlogStore) CreateBlog(blog models.Blog) (models.Blog, error) {
+	var created models.Blog
+	var err error
+	c.write(func() { created, err = c.store.CreateBlog(blog) })
+	if err != nil {
+		return created, err
+	}
+
+	c.publish(ChangeEvent{Type: BlogCreated, ID: created.ID, Slug: created.Slug})
+	return created, nil
+}
+
+func (c *CachedBlogStore) UpdateBlogBySlug(slug string, updates models.UpdateBlogRequest) (*models.Blog, error) {
+	var updated *models.Blog
+	var err error
+	c.write(func() { updated, err = c.store.UpdateBlogBySlug(slug, updates) })
+	if err != nil {
+		return nil, err
+	}
+
+	c.publishUpdate(slug, updated)
+	return updated, nil
+}
+
+func (c *CachedBlogStore) DeleteBlogBySlug(slug string) error {
+	// Look the blog up first; events identify blogs by ID
+	existing, _ := c.GetBlogBySlug(slug)
+
+	var err error
+	c.write(func() { err = c.store.DeleteBlogBySlug(slug) })
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		c.publish(ChangeEvent{Type: BlogDeleted, ID: existing.ID, Slug: slug})
+	}
+	return nil
+}
+
+// SaveBlogImage implements the BlogStore interface. Images are not part of
+// the index, so the cache is left intact.
+func (c *CachedBlogStore) SaveBlogImage(slug string, imageFilename string, imageData []byte) error {
+	return c.store.SaveBlogImage(slug, imageFilename, imageData)
+}
+
+// GetBlogImage implements the BlogStore interface
+func (c *CachedBlogStore) GetBlogImage(slug string, imageFilename string) ([]byte, error) {
+	return c.store.GetBlogImage(slug, imageFilename)
+}
+
+// revisionStore returns the wrapped store's revision support, if any
+func (c *CachedBlogStore) revisionStore() (models.RevisionStore, error) {
+	revisions, ok := c.store.(models.RevisionStore)
+	if !ok {
+		return nil, errors.New("revisions not supported")
+	}
+	return revisions, nil
+}
+
+// ListRevisions implements the RevisionStore interface
+func (c *CachedBlogStore) ListRevisions(slug string) ([]models.Revision, error) {
+	revisions, err := c.revisionStore()
+	if err != nil {
+		return nil, err
+	}
+	return revisions.ListRevisions(slug)
+}
+
+// GetRevision implements the RevisionStore interface
+func (c *CachedBlogStore) GetRevision(slug string, revisionID int) (*models.Revision, error) {
+	revisions, err := c.revisionStore()
+	if err != nil {
+		return nil, err
+	}
+	return revisions.GetRevision(slug, revisionID)
+}
+
+// RestoreRevision implements the RevisionStore interface
+func (c *CachedBlogStore) RestoreRevision(slug string, revisionID int) (*models.Blog, error) {
+	revisions, err := c.revisionStore()
+	if err != nil {
+		return nil, err
+	}
+
+	var restored *models.Blog
+	c.write(func() { restored, err = revisions.RestoreRevision(slug, revisionID) })
+	if err != nil {
+		return nil, err
+	}
+
+	c.publishUpdate(slug, restored)
+	return restored, nil
+}
+
+// trashStore returns the wrapped store's trash support, if any
+func (c *CachedBlogStore) trashStore() (models.TrashStore, error) {
+	trash, ok := c.store.(models.TrashStore)
+	if !ok {
+		return nil, errors.New("trash not supported")
+	}
+	return trash, nil
+}
+
+// ListTrash implements the TrashStore interface
+func (c *CachedBlogStore) ListTrash() ([]models.TrashedBlog, error) {
+	trash, err := c.trashStore()
+	if err != nil {
+		return nil, err
+	}
+	return trash.ListTrash()
+}
+
+// RestoreTrashedBlog implements the TrashStore interface
+func (c *CachedBlogStore) RestoreTrashedBlog(id uuid.UUID) (*models.Blog, error) {
+	trash, err := c.trashStore()
+	if err != nil {
+		return nil, err
+	}
+
+	var restored *models.Blog
+	c.write(func() { restored, err = trash.RestoreTrashedBlog(id) })
+	if err != nil {
+		return nil, err
+	}
+
+	c.publish(ChangeEvent{Type: BlogCreated, ID: restored.ID, Slug: restored.Slug})
+	return restored, nil
+}
+
+// PurgeTrash implements the TrashStore interface. Trashed blogs are not part
+// of the index, so the cache is left intact.
+func (c *CachedBlogStore) PurgeTrash(deletedBefore time.Time) (int, error) {
+	trash, err := c.trashStore()
+	if err != nil {
+		return 0, err
+	}
+	return trash.PurgeTrash(deletedBefore)
+}
+
+// ResolveSlug implements the SlugHistoryStore interface. Blogs currently
+// using the slug are served from the index; former slugs are looked up in
+// the wrapped store.
+func (c *CachedBlogStore) ResolveSlug(slug string) (*models.Blog, error) {
+	if blog, err := c.GetBlogBySlug(slug); err == nil {
+		return blog, nil
+	}
+
+	history, ok := c.store.(models.SlugHistoryStore)
+	if !ok {
+		return nil, errors.New("blog not found")
+	}
+	return history.ResolveSlug(slug)
+}
+
+// mediaLibrary returns the wrapped store's media library, if any
+func (c *CachedBlogStore) mediaLibrary() (models.MediaLibrary, error) {
+	library, ok := c.store.(models.MediaLibrary)
+	if !ok {
+		return nil, errors.New("media library not supported")
+	}
+	return library, nil
+}
+
+// SaveAsset implements the MediaLibrary interface. Library assets are not
+// part of the index, so the cache is left intact, here and below.
+func (c *CachedBlogStore) SaveAsset(asset models.MediaAsset, files map[string][]byte) (*models.MediaAsset, error) {
+	library, err := c.mediaLibrary()
+	if err != nil {
+		return nil, err
+	}
+	return library.SaveAsset(asset, files)
+}
+
+// GetAsset implements the MediaLibrary interface
+func (c *CachedBlogStore) GetAsset(hash string) (*models.MediaAsset, error) {
+	library, err := c.mediaLibrary()
+	if err != nil {
+		return nil, err
+	}
+	return library.GetAsset(hash)
+}
+
+// GetAssetFile implements the MediaLibrary interface
+func (c *CachedBlogStore) GetAssetFile(hash string, filename string) ([]byte, error) {
+	library, err := c.mediaLibrary()
+	if err != nil {
+		return nil, err
+	}
+	return library.GetAssetFile(hash, filename)
+}
+
+// ListAssets implements the MediaLibrary interface
+func (c *CachedBlogStore) ListAssets() ([]models.MediaAsset, error) {
+	library, err := c.mediaLibrary()
+	if err != nil {
+		return nil, err
+	}
+	return library.ListAssets()
+}
+
+// AssetUsage implements the MediaLibrary interface
+func (c *CachedBlogStore) AssetUsage(hash string) ([]models.MediaUsage, error) {
+	library, err := c.mediaLibrary()
+	if err != nil {
+		return nil, err
+	}
+	return library.AssetUsage(hash)
+}
+
+// CollectGarbage implements the MediaLibrary interface
+func (c *CachedBlogStore) CollectGarbage(uploadedBefore time.Time) (int, error) {
+	library, err := c.mediaLibrary()
+	if err != nil {
+		return 0, err
+	}
+	return library.CollectGarbage(uploadedBefore)
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChangeType describes what happened to a blog
+type ChangeType string
+
+const (
+	BlogCreated ChangeType = "created"
+	BlogUpdated ChangeType = "updated"
+	BlogRenamed ChangeType = "renamed"
+	BlogDeleted ChangeType = "deleted"
+)
+
+// ChangeEvent describes a change to a single blog
+type ChangeEvent struct {
+	Type    ChangeType
+	ID      uuid.UUID
+	Slug    string
+	OldSlug string // Previous slug, only set for BlogRenamed
+	Time    time.Time
+}
+
+// EventBus fans blog change events out to any number of subscribers, so
+// subsystems like the sitemap, feeds or search can react to content changes
+type EventBus struct {
+	mu          sync.RWMutex
+	nextID      int
+	subscribers map[int]func(ChangeEvent)
+}
+
+// NewEventBus creates an event bus with no subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[int]func(ChangeEvent))}
+}
+
+// Subscribe registers fn to be called for every published event and returns
+// a function that removes the subscription. Subscribers are called
+// synchronously from the publishing goroutine, in the order they subscribed,
+// and should return quickly.
+func (b *EventBus) Subscribe(fn func(ChangeEvent)) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = fn
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscribers, id)
+	}
+}
+
+// Publish delivers event to every subscriber
+func (b *EventBus) Publish(event ChangeEvent) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	ids := make([]int, 0, len(b.subscribers))
+	for id := range b.subscribers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	subscribers := make([]func(ChangeEvent), 0, len(ids))
+	for _, id := range ids {
+		subscribers = append(subscribers, b.subscribers[id])
+	}
+	b.mu.RUnlock()
+
+	for _, fn := range subscribers {
+		fn(event)
+	}
+}
@@ -0,0 +1,351 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"go-react-backend/models"
+
+	"github.com/google/uuid"
+)
+
+// mediaDirName is the hidden directory under the data directory that holds
+// the media library, one directory per asset hash
+const mediaDirName = ".media"
+
+// assetInfoName is the file inside an asset directory that describes it
+const assetInfoName = "asset.json"
+
+// getAssetDir returns the directory holding a library asset
+func (s *FileBlogStore) getAssetDir(hash string) string {
+	return filepath.Join(s.dataDir, mediaDirName, hash)
+}
+
+// loadAsset reads a library asset's description. The caller must hold the
+// lock.
+func (s *FileBlogStore) loadAsset(hash string) (*models.MediaAsset, error) {
+	if !models.ValidMediaHash(hash) {
+		return nil, errors.New("asset not found")
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.getAssetDir(hash), assetInfoName))
+	if os.IsNotExist(err) {
+		return nil, errors.New("asset not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read asset: %w", err)
+	}
+
+	var asset models.MediaAsset
+	if err := json.Unmarshal(data, &asset); err != nil {
+		return nil, fmt.Errorf("invalid asset %s: %w", hash, err)
+	}
+	return &asset, nil
+}
+
+// writeAssetInfo writes an asset's description into dir
+func writeAssetInfo(dir string, asset models.MediaAsset) error {
+	asset.RefCount = 0 // Always worked out from the blogs
+	data, err := json.MarshalIndent(asset, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal asset: %w", err)
+	}
+	return writeFileAtomic(filepath.Join(dir, assetInfoName), data, 0644)
+}
+
+// loadAssets reads every asset in the library. The caller must hold the lock.
+func (s *FileBlogStore) loadAssets() ([]models.MediaAsset, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dataDir, mediaDirName))
+	if os.IsNotExist(err) {
+		return []models.MediaAsset{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read media library: %w", err)
+	}
+
+	assets := []models.MediaAsset{}
+	for _, entry := range entries {
+		if !entry.IsDir() || isHiddenName(entry.Name()) {
+			continue
+		}
+		asset, err := s.loadAsset(entry.Name())
+		if err != nil {
+			continue // Skip entries that aren't valid assets
+		}
+		assets = append(assets, *asset)
+	}
+	return assets, nil
+}
+
+// mediaUsage maps asset hashes to the media items using them, in live blogs
+// and in the trash. The caller must hold the lock.
+func (s *FileBlogStore) mediaUsage() (map[string][]models.MediaUsage, error) {
+	blogs, err := s.loadAllBlogs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load blogs: %w", err)
+	}
+	trashed, err := s.loadTrash()
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string][]models.MediaUsage)
+	add := func(blog models.Blog, inTrash bool) {
+		for _, item := range blog.Media {
+			if item.Hash == "" {
+				continue
+			}
+			usage[item.Hash] = append(usage[item.Hash], models.MediaUsage{
+				BlogID:   blog.ID,
+				Slug:     blog.Slug,
+				Title:    blog.Title,
+				Filename: item.Filename,
+				Trashed:  inTrash,
+			})
+		}
+	}
+	for _, blog := range blogs {
+		add(blog, false)
+	}
+	for _, entry := range trashed {
+		add(entry.Blog, true)
+	}
+	return usage, nil
+}
+
+// revisionHashes returns the hashes of the assets used by revisions of
+// blogs, live or in the trash. Restoring a revision brings its media back,
+// so garbage collection keeps them. The caller must hold the lock.
+func (s *FileBlogStore) revisionHashes() (map[string]bool, error) {
+	blogs, err := s.loadAllBlogs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load blogs: %w", err)
+	}
+	var revisionsDirs []string
+	for _, blog := range blogs {
+		revisionsDirs = append(revisionsDirs, s.getBlogRevisionsDir(blog.Slug))
+	}
+
+	trashEntries, err := os.ReadDir(filepath.Join(s.dataDir, trashDirName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+	for _, entry := range trashEntries {
+		if entry.IsDir() {
+			revisionsDirs = append(revisionsDirs, filepath.Join(s.dataDir, trashDirName, entry.Name(), revisionsDirName))
+		}
+	}
+
+	hashes := make(map[string]bool)
+	for _, revisionsDir := range revisionsDirs {
+		revisions, err := loadRevisionsDir(revisionsDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, revision := range revisions {
+			for _, item := range revision.Previous.Media {
+				if item.Hash != "" {
+					hashes[item.Hash] = true
+				}
+			}
+		}
+	}
+	return hashes, nil
+}
+
+// countBlogs returns how many distinct blogs usage comes from
+func countBlogs(usage []models.MediaUsage) int {
+	blogs := make(map[uuid.UUID]bool)
+	for _, use := range usage {
+		blogs[use.BlogID] = true
+	}
+	return len(blogs)
+}
+
+// SaveAsset implements the MediaLibrary interface. A new asset's files are
+// written to a temp directory that is renamed into place, so the asset
+// appears complete or not at all.
+func (s *FileBlogStore) SaveAsset(asset models.MediaAsset, files map[string][]byte) (*models.MediaAsset, error) {
+	if !models.ValidMediaHash(asset.Hash) {
+		return nil, fmt.Errorf("invalid asset hash %q", asset.Hash)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	existing, err := s.loadAsset(asset.Hash)
+	if err == nil {
+		existing.Uploaded = now
+		if err := writeAssetInfo(s.getAssetDir(asset.Hash), *existing); err != nil {
+			return nil, fmt.Errorf("failed to update asset: %w", err)
+		}
+		return existing, nil
+	}
+	if err.Error() != "asset not found" {
+		return nil, err
+	}
+
+	mediaRoot := filepath.Join(s.dataDir, mediaDirName)
+	if err := os.MkdirAll(mediaRoot, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create media directory: %w", err)
+	}
+	tmpDir, err := os.MkdirTemp(mediaRoot, "."+asset.Hash+tempFileMarker+"*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create asset directory: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			os.RemoveAll(tmpDir)
+		}
+	}()
+
+	asset.Size = 0
+	for _, filename := range asset.Files() {
+		data, ok := files[filename]
+		if !ok || filepath.Base(filename) != filename {
+			return nil, fmt.Errorf("missing or invalid asset file %q", filename)
+		}
+		if err := writeFileAtomic(filepath.Join(tmpDir, filename), data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write asset file: %w", err)
+		}
+		asset.Size += int64(len(data))
+	}
+	asset.Created = now
+	asset.Uploaded = now
+	if err := writeAssetInfo(tmpDir, asset); err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(tmpDir, s.getAssetDir(asset.Hash)); err != nil {
+		return nil, fmt.Errorf("failed to store asset: %w", err)
+	}
+	committed = true
+
+	fmt.Printf("🖼️ Stored media asset %s (%d bytes)\n", asset.Hash, asset.Size)
+	return &asset, syncDir(mediaRoot)
+}
+
+// GetAsset implements the MediaLibrary interface
+func (s *FileBlogStore) GetAsset(hash string) (*models.MediaAsset, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	asset, err := s.loadAsset(hash)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.mediaUsage()
+	if err != nil {
+		return nil, err
+	}
+	asset.RefCount = countBlogs(usage[hash])
+	return asset, nil
+}
+
+// GetAssetFile implements the MediaLibrary interface
+func (s *FileBlogStore) GetAssetFile(hash string, filename string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	asset, err := s.loadAsset(hash)
+	if err != nil {
+		return nil, err
+	}
+	if !asset.HasFile(filename) {
+		return nil, errors.New("image not found")
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.getAssetDir(hash), filename))
+	if os.IsNotExist(err) {
+		return nil, errors.New("image not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read asset file: %w", err)
+	}
+	return data, nil
+}
+
+// ListAssets implements the MediaLibrary interface
+func (s *FileBlogStore) ListAssets() ([]models.MediaAsset, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	assets, err := s.loadAssets()
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.mediaUsage()
+	if err != nil {
+		return nil, err
+	}
+	for i := range assets {
+		assets[i].RefCount = countBlogs(usage[assets[i].Hash])
+	}
+
+	sort.Slice(assets, func(i, j int) bool {
+		return assets[i].Created.After(assets[j].Created)
+	})
+	return assets, nil
+}
+
+// AssetUsage implements the MediaLibrary interface
+func (s *FileBlogStore) AssetUsage(hash string) ([]models.MediaUsage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.loadAsset(hash); err != nil {
+		return nil, err
+	}
+	usage, err := s.mediaUsage()
+	if err != nil {
+		return nil, err
+	}
+	if usage[hash] == nil {
+		return []models.MediaUsage{}, nil
+	}
+	return usage[hash], nil
+}
+
+// CollectGarbage implements the MediaLibrary interface. Assets that only
+// revisions use are kept. The write lock keeps blogs from starting to use an
+// asset while it is being deleted.
+func (s *FileBlogStore) CollectGarbage(uploadedBefore time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	assets, err := s.loadAssets()
+	if err != nil {
+		return 0, err
+	}
+	usage, err := s.mediaUsage()
+	if err != nil {
+		return 0, err
+	}
+	inRevisions, err := s.revisionHashes()
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, asset := range assets {
+		if len(usage[asset.Hash]) > 0 || inRevisions[asset.Hash] || !asset.Uploaded.Before(uploadedBefore) {
+			continue
+		}
+		if err := os.RemoveAll(s.getAssetDir(asset.Hash)); err != nil {
+			return deleted, fmt.Errorf("failed to delete asset %s: %w", asset.Hash, err)
+		}
+		deleted++
+	}
+
+	if deleted > 0 {
+		return deleted, syncDir(filepath.Join(s.dataDir, mediaDirName))
+	}
+	return 0, nil
+}
@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-react-backend/models"
+)
+
+// revisionsDirName is the hidden directory inside each blog directory that
+// holds its revision log, one JSON file per revision
+const revisionsDirName = ".revisions"
+
+// getBlogRevisionsDir returns the revision log directory for a blog
+func (s *FileBlogStore) getBlogRevisionsDir(slug string) string {
+	return filepath.Join(s.dataDir, slug, revisionsDirName)
+}
+
+// loadRevisions loads every revision of a blog, oldest first
+func (s *FileBlogStore) loadRevisions(slug string) ([]models.Revision, error) {
+	return loadRevisionsDir(s.getBlogRevisionsDir(slug))
+}
+
+// loadRevisionsDir loads every revision in a revision log directory, oldest
+// first
+func loadRevisionsDir(revisionsDir string) ([]models.Revision, error) {
+	entries, err := os.ReadDir(revisionsDir)
+	if os.IsNotExist(err) {
+		return []models.Revision{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read revisions: %w", err)
+	}
+
+	revisions := []models.Revision{}
+	for _, entry := range entries {
+		if entry.IsDir() || isHiddenName(entry.Name()) || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(revisionsDir, entry.Name()))
+		if err != nil {
+			continue // Skip revisions that can't be read
+		}
+
+		var revision models.Revision
+		if err := json.Unmarshal(data, &revision); err != nil {
+			continue // Skip revisions that can't be parsed
+		}
+		revisions = append(revisions, revision)
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].ID < revisions[j].ID
+	})
+
+	return revisions, nil
+}
+
+// saveRevision appends a revision to a blog's log, assigning it the next ID,
+// and returns the path of the written file
+func (s *FileBlogStore) saveRevision(slug string, revision models.Revision) (string, error) {
+	revisions, err := s.loadRevisions(slug)
+	if err != nil {
+		return "", err
+	}
+
+	revision.ID = 1
+	if len(revisions) > 0 {
+		revision.ID = revisions[len(revisions)-1].ID + 1
+	}
+
+	revisionsDir := s.getBlogRevisionsDir(slug)
+	if err := os.MkdirAll(revisionsDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create revisions directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(revision, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal revision: %w", err)
+	}
+
+	revisionPath := filepath.Join(revisionsDir, strconv.Itoa(revision.ID)+".json")
+	if err := writeFileAtomic(revisionPath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write revision: %w", err)
+	}
+
+	return revisionPath, nil
+}
+
+// blogExists reports whether a blog with the given slug exists. The caller
+// must hold the lock.
+func (s *FileBlogStore) blogExists(slug string) (bool, error) {
+	blogs, err := s.loadAllBlogs()
+	if err != nil {
+		return false, err
+	}
+	for _, blog := range blogs {
+		if blog.Slug == slug {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// loadBlogBySlug loads the blog with the given slug. The caller must hold
+// the lock.
+func (s *FileBlogStore) loadBlogBySlug(slug string) (*models.Blog, error) {
+	blogs, err := s.loadAllBlogs()
+	if err != nil {
+		return nil, err
+	}
+	for _, blog := range blogs {
+		if blog.Slug == slug {
+			return &blog, nil
+		}
+	}
+	return nil, errors.New("blog not found")
+}
+
+// getRevision finds a single revision. The caller must hold the lock.
+func (s *FileBlogStore) getRevision(slug string, revisionID int) (*models.Revision, error) {
+	exists, err := s.blogExists(slug)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("blog not found")
+	}
+
+	revisions, err := s.loadRevisions(slug)
+	if err != nil {
+		return nil, err
+	}
+	for _, revision := range revisions {
+		if revision.ID == revisionID {
+			return &revision, nil
+		}
+	}
+
+	return nil, errors.New("revision not found")
+}
+
+// ListRevisions implements the RevisionStore interface, newest first
+func (s *FileBlogStore) ListRevisions(slug string) ([]models.Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	exists, err := s.blogExists(slug)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("blog not found")
+	}
+
+	revisions, err := s.loadRevisions(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].ID > revisions[j].ID
+	})
+	return revisions, nil
+}
+
+// GetRevision implements the RevisionStore interface
+func (s *FileBlogStore) GetRevision(slug string, revisionID int) (*models.Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.getRevision(slug, revisionID)
+}
+
+// RestoreRevision implements the RevisionStore interface. The restore is a
+// regular update, so the version it replaces becomes a new revision.
+func (s *FileBlogStore) RestoreRevision(slug string, revisionID int) (*models.Blog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	revision, err := s.getRevision(slug, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.loadBlogBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateBlogBySlug(slug, revision.RestoreRequest(current))
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go-react-backend/models"
+)
+
+// slugHistoryName is the file inside each blog directory listing the slugs
+// the blog was previously published under, oldest first
+const slugHistoryName = ".slug-history.json"
+
+// getSlugHistoryPath returns the slug history file for a blog
+func (s *FileBlogStore) getSlugHistoryPath(slug string) string {
+	return filepath.Join(s.dataDir, slug, slugHistoryName)
+}
+
+// loadSlugHistory loads a blog's former slugs, oldest first
+func (s *FileBlogStore) loadSlugHistory(slug string) ([]models.SlugChange, error) {
+	data, err := os.ReadFile(s.getSlugHistoryPath(slug))
+	if os.IsNotExist(err) {
+		return []models.SlugChange{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read slug history: %w", err)
+	}
+
+	var history []models.SlugChange
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("failed to parse slug history: %w", err)
+	}
+	return history, nil
+}
+
+// saveSlugHistory replaces a blog's slug history, removing the file when the
+// history is empty
+func (s *FileBlogStore) saveSlugHistory(slug string, history []models.SlugChange) error {
+	if len(history) == 0 {
+		if err := os.Remove(s.getSlugHistoryPath(slug)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove slug history: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal slug history: %w", err)
+	}
+	if err := writeFileAtomic(s.getSlugHistoryPath(slug), data, 0644); err != nil {
+		return fmt.Errorf("failed to write slug history: %w", err)
+	}
+	return nil
+}
+
+// ResolveSlug implements the SlugHistoryStore interface. Every former slug of
+// a blog stays in its history, so chained renames resolve straight to the
+// current slug.
+func (s *FileBlogStore) ResolveSlug(slug string) (*models.Blog, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	blogs, err := s.loadAllBlogs()
+	if err != nil {
+		return nil, err
+	}
+
+	var resolved *models.Blog
+	var resolvedAt models.SlugChange
+	for i := range blogs {
+		if blogs[i].Slug == slug {
+			return &blogs[i], nil
+		}
+
+		history, err := s.loadSlugHistory(blogs[i].Slug)
+		if err != nil {
+			continue // Skip blogs whose history can't be read
+		}
+		for _, change := range history {
+			if change.Slug == slug && (resolved == nil || change.ChangedAt.After(resolvedAt.ChangedAt)) {
+				resolved = &blogs[i]
+				resolvedAt = change
+			}
+		}
+	}
+
+	if resolved == nil {
+		return nil, errors.New("blog not found")
+	}
+	return resolved, nil
+}
+
+// appendSlugChange returns a new history with change as its latest entry,
+// dropping any earlier entry for the same slug
+func appendSlugChange(history []models.SlugChange, change models.SlugChange) []models.SlugChange {
+	updated := make([]models.SlugChange, 0, len(history)+1)
+	for _, existing := range history {
+		if existing.Slug != change.Slug {
+			updated = append(updated, existing)
+		}
+	}
+	return append(updated, change)
+}
@@ -0,0 +1,382 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Temporary files and blog journals are dotfiles so that neither
+// loadAllBlogs nor the watcher mistake them for blog content
+const (
+	tempFileMarker    = ".tmp-"
+	blogJournalGlob   = ".txn-*.json"
+	blogJournalPrefix = ".txn-"
+)
+
+// writeFileAtomic replaces path with data so that readers (and a crash at any
+// point) see either the old file or the new one, never a partial write. The
+// data is written to a temp file in the same directory, fsynced, renamed over
+// path, and the directory is fsynced so the rename itself is durable.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmpPath, err := stageFile(path, data, perm)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+// stageFile writes data to a fsynced temp file next to path, ready to be
+// renamed over it, and returns the temp file's path
+func stageFile(path string, data []byte, perm os.FileMode) (string, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+tempFileMarker+"*")
+	if err != nil {
+		return "", err
+	}
+	tmpPath := tmp.Name()
+
+	// Clean up the temp file on any failure
+	staged := false
+	defer func() {
+		if !staged {
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return "", err
+	}
+	staged = true
+
+	return tmpPath, nil
+}
+
+// backupPath names the copy of path kept while a transaction replaces it.
+// It carries the temp file marker, so recovery sweeps up stray backups.
+func backupPath(path string) string {
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+tempFileMarker+"backup")
+}
+
+// syncDir fsyncs a directory so that entries created, renamed or removed in
+// it survive a crash
+func syncDir(dir string) error {
+	// Windows cannot fsync directories; renames there are already durable
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
+
+// blogJournal is written before a blog's directory is renamed or its files
+// are replaced, so that an interrupted update can be rolled back on the next
+// startup. Removing it commits the update.
+type blogJournal struct {
+	ID      string        `json:"id"`
+	OldSlug string        `json:"old_slug"`
+	NewSlug string        `json:"new_slug"`
+	Files   []journalFile `json:"files,omitempty"`
+}
+
+// journalFile is a file replaced by a blog update
+type journalFile struct {
+	Path    string `json:"path"`    // Relative to the data directory
+	Existed bool   `json:"existed"` // Whether there was a file to back up
+}
+
+// stagedFile is a file of a blog update, written to a temp file until the
+// update replaces the real one
+type stagedFile struct {
+	path    string
+	tmpPath string
+	existed bool
+}
+
+// blogTxn groups the filesystem changes of a single blog update: an optional
+// directory rename followed by replacing the blog's files. If anything fails
+// the files and the rename are rolled back, so the post is never left with
+// new content and old metadata, or under its old slug with new metadata.
+type blogTxn struct {
+	store       *FileBlogStore
+	journal     blogJournal
+	journalPath string
+	renamed     bool
+	files       []stagedFile
+	replaced    int // How many of files have been renamed into place
+}
+
+// beginBlogTxn starts an update of the blog with the given ID, moving its
+// directory from oldSlug to newSlug when they differ
+func (s *FileBlogStore) beginBlogTxn(id string, oldSlug string, newSlug string) (*blogTxn, error) {
+	txn := &blogTxn{
+		store:       s,
+		journal:     blogJournal{ID: id, OldSlug: oldSlug, NewSlug: newSlug},
+		journalPath: filepath.Join(s.dataDir, blogJournalPrefix+id+".json"),
+	}
+	if oldSlug == newSlug {
+		return txn, nil
+	}
+
+	newDir := s.GetBlogDir(newSlug)
+	if _, err := os.Stat(newDir); err == nil {
+		return nil, fmt.Errorf("failed to rename blog directory: %s already exists", newDir)
+	}
+
+	// Record the intent before touching the directory
+	if err := txn.writeJournal(); err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(s.GetBlogDir(oldSlug), newDir); err != nil {
+		os.Remove(txn.journalPath)
+		return nil, fmt.Errorf("failed to rename blog directory: %w", err)
+	}
+	txn.renamed = true
+
+	if err := syncDir(s.dataDir); err != nil {
+		txn.rollback()
+		return nil, fmt.Errorf("failed to sync data directory: %w", err)
+	}
+
+	return txn, nil
+}
+
+// writeJournal records the transaction as it stands
+func (t *blogTxn) writeJournal() error {
+	data, err := json.Marshal(t.journal)
+	if err != nil {
+		return fmt.Errorf("failed to marshal blog journal: %w", err)
+	}
+	if err := writeFileAtomic(t.journalPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write blog journal: %w", err)
+	}
+	return nil
+}
+
+// writeFiles replaces the files at paths with data, in that order, as part
+// of the transaction. All of them are staged as temp files and the current
+// versions backed up before any is replaced, so rollback can restore them
+// all. On error the caller must roll back.
+func (t *blogTxn) writeFiles(paths []string, data map[string][]byte) error {
+	for _, path := range paths {
+		tmpPath, err := stageFile(path, data[path], 0644)
+		if err != nil {
+			return err
+		}
+		_, statErr := os.Stat(path)
+		t.files = append(t.files, stagedFile{path: path, tmpPath: tmpPath, existed: statErr == nil})
+	}
+
+	// Back up the current files and say so in the journal before replacing
+	// any of them
+	for _, file := range t.files {
+		relPath, err := filepath.Rel(t.store.dataDir, file.path)
+		if err != nil {
+			return err
+		}
+		t.journal.Files = append(t.journal.Files, journalFile{Path: relPath, Existed: file.existed})
+
+		if file.existed {
+			current, err := os.ReadFile(file.path)
+			if err == nil {
+				err = writeFileAtomic(backupPath(file.path), current, 0644)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to back up %s: %w", filepath.Base(file.path), err)
+			}
+		}
+	}
+	if err := t.writeJournal(); err != nil {
+		return err
+	}
+
+	for _, file := range t.files {
+		if err := os.Rename(file.tmpPath, file.path); err != nil {
+			return fmt.Errorf("failed to write %s: %w", filepath.Base(file.path), err)
+		}
+		t.replaced++
+	}
+	for _, file := range t.files {
+		if err := syncDir(filepath.Dir(file.path)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restoreFiles puts back the files writeFiles replaced as they were, removing
+// those that didn't exist, and cleans up temp files and backups. It reports
+// false, keeping the backups for recovery, if a file couldn't be restored.
+func (t *blogTxn) restoreFiles() bool {
+	for i := t.replaced - 1; i >= 0; i-- {
+		file := t.files[i]
+		var err error
+		if file.existed {
+			err = os.Rename(backupPath(file.path), file.path)
+		} else {
+			err = os.Remove(file.path)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			fmt.Printf("❌ Failed to restore %s: %v\n", file.path, err)
+			return false
+		}
+	}
+
+	for _, file := range t.files {
+		os.Remove(file.tmpPath)
+		os.Remove(backupPath(file.path))
+	}
+	t.files, t.replaced = nil, 0
+	return true
+}
+
+// commit finishes the transaction once the blog files have been written
+func (t *blogTxn) commit() error {
+	if t.renamed || len(t.files) > 0 {
+		if err := os.Remove(t.journalPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove blog journal: %w", err)
+		}
+		if err := syncDir(t.store.dataDir); err != nil {
+			return err
+		}
+	}
+	for _, file := range t.files {
+		os.Remove(backupPath(file.path))
+	}
+	return nil
+}
+
+// rollback restores the blog's files and undoes the directory rename, if any.
+// Whatever it can't undo is left, with the journal, for recovery at startup.
+func (t *blogTxn) rollback() {
+	if !t.restoreFiles() {
+		return
+	}
+
+	if t.renamed {
+		oldDir := t.store.GetBlogDir(t.journal.OldSlug)
+		newDir := t.store.GetBlogDir(t.journal.NewSlug)
+		if err := os.Rename(newDir, oldDir); err != nil {
+			// Leave the journal in place so recovery can retry at startup
+			fmt.Printf("❌ Failed to roll back rename of %s to %s: %v\n", newDir, oldDir, err)
+			return
+		}
+		syncDir(t.store.dataDir)
+	}
+	os.Remove(t.journalPath)
+}
+
+// recover cleans up after a crash: any blog update that did not complete is
+// rolled back and leftover temp files are removed
+func (s *FileBlogStore) recover() error {
+	journals, err := filepath.Glob(filepath.Join(s.dataDir, blogJournalGlob))
+	if err != nil {
+		return err
+	}
+	for _, journalPath := range journals {
+		s.recoverBlogTxn(journalPath)
+	}
+
+	// Temp files live next to the file they were replacing, wherever that
+	// is: blog directories, their revision logs, the trash, the media
+	// library (which writes assets to temp directories) and so on
+	return filepath.WalkDir(s.dataDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			fmt.Printf("❌ Failed to scan %s for temp files: %v\n", path, err)
+			return nil
+		}
+		if !isHiddenName(entry.Name()) || !strings.Contains(entry.Name(), tempFileMarker) {
+			return nil
+		}
+
+		if err := os.RemoveAll(path); err != nil {
+			fmt.Printf("❌ Failed to remove leftover temp file %s: %v\n", path, err)
+		} else {
+			fmt.Printf("🧹 Removed leftover temp file %s\n", path)
+		}
+		if entry.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+// recoverBlogTxn rolls back one interrupted blog update: its files are
+// restored from their backups, then its directory rename is undone
+func (s *FileBlogStore) recoverBlogTxn(journalPath string) {
+	data, err := os.ReadFile(journalPath)
+	if err != nil {
+		fmt.Printf("❌ Failed to read blog journal %s: %v\n", journalPath, err)
+		return
+	}
+
+	var journal blogJournal
+	if err := json.Unmarshal(data, &journal); err != nil || journal.OldSlug == "" || journal.NewSlug == "" {
+		// The journal itself was never fully written, so nothing changed
+		os.Remove(journalPath)
+		return
+	}
+
+	for _, file := range journal.Files {
+		path := filepath.Join(s.dataDir, file.Path)
+		if file.Existed {
+			// Without a backup, the file was never replaced
+			if _, err := os.Stat(backupPath(path)); err != nil {
+				continue
+			}
+			err = os.Rename(backupPath(path), path)
+		} else {
+			err = os.Remove(path)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			fmt.Printf("❌ Failed to restore %s after an interrupted update: %v\n", path, err)
+			return
+		}
+	}
+	if len(journal.Files) > 0 {
+		fmt.Printf("🧹 Rolled back interrupted update of %s\n", journal.NewSlug)
+	}
+
+	if journal.OldSlug != journal.NewSlug {
+		oldDir := s.GetBlogDir(journal.OldSlug)
+		newDir := s.GetBlogDir(journal.NewSlug)
+		if _, err := os.Stat(newDir); err == nil {
+			if _, err := os.Stat(oldDir); os.IsNotExist(err) {
+				if err := os.Rename(newDir, oldDir); err != nil {
+					fmt.Printf("❌ Failed to roll back interrupted rename %s -> %s: %v\n", journal.OldSlug, journal.NewSlug, err)
+					return
+				}
+				fmt.Printf("🧹 Rolled back interrupted rename %s -> %s\n", journal.OldSlug, journal.NewSlug)
+			}
+		}
+	}
+
+	os.Remove(journalPath)
+	syncDir(s.dataDir)
+}
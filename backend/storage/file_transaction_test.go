@@ -0,0 +1,289 @@
+package storage
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-react-backend/models"
+)
+
+// blogFiles reads a blog directory's content.md and metadata.json
+func blogFiles(t *testing.T, blogDir string) (content string, metadata string) {
+	t.Helper()
+	contentData, err := os.ReadFile(filepath.Join(blogDir, "content.md"))
+	if err != nil {
+		t.Fatalf("reading content.md: %v", err)
+	}
+	metadataData, err := os.ReadFile(filepath.Join(blogDir, "metadata.json"))
+	if err != nil {
+		t.Fatalf("reading metadata.json: %v", err)
+	}
+	return string(contentData), string(metadataData)
+}
+
+// leftovers lists the journals, temp files and backups under dataDir
+func leftovers(t *testing.T, dataDir string) []string {
+	t.Helper()
+	var found []string
+	err := filepath.WalkDir(dataDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.Contains(entry.Name(), tempFileMarker) || strings.HasPrefix(entry.Name(), blogJournalPrefix) {
+			found = append(found, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkDir: %v", err)
+	}
+	return found
+}
+
+// edited returns blog with new content and title, as an update would save it
+func edited(blog models.Blog) models.Blog {
+	blog.Title = "New title"
+	blog.Content = "new content"
+	return blog
+}
+
+func TestRecoverInterruptedBlogUpdate(t *testing.T) {
+	tests := []struct {
+		name string
+		// crash leaves the update of blog, saved under "post", unfinished
+		crash func(t *testing.T, s *FileBlogStore, blog models.Blog)
+		// wantSlug is where the blog should be after recovery, with its
+		// files as they were before the update
+		wantSlug string
+	}{
+		{
+			name: "after the directory rename",
+			crash: func(t *testing.T, s *FileBlogStore, blog models.Blog) {
+				if _, err := s.beginBlogTxn(blog.ID.String(), "post", "renamed"); err != nil {
+					t.Fatalf("beginBlogTxn: %v", err)
+				}
+			},
+			wantSlug: "post",
+		},
+		{
+			name: "after replacing content.md but not metadata.json",
+			crash: func(t *testing.T, s *FileBlogStore, blog models.Blog) {
+				txn, err := s.beginBlogTxn(blog.ID.String(), "post", "post")
+				if err != nil {
+					t.Fatalf("beginBlogTxn: %v", err)
+				}
+				metadataPath := s.getBlogMetadataPath("post")
+				oldMetadata, err := os.ReadFile(metadataPath)
+				if err != nil {
+					t.Fatalf("ReadFile: %v", err)
+				}
+				if err := s.saveBlog(txn, edited(blog), "post"); err != nil {
+					t.Fatalf("saveBlog: %v", err)
+				}
+
+				// Put the disk back to how it was between the two renames:
+				// metadata.json still old, its new version still staged
+				if err := os.WriteFile(metadataPath, oldMetadata, 0644); err != nil {
+					t.Fatalf("WriteFile: %v", err)
+				}
+				staged := filepath.Join(s.GetBlogDir("post"), ".metadata.json"+tempFileMarker+"123")
+				if err := os.WriteFile(staged, []byte("{}"), 0644); err != nil {
+					t.Fatalf("WriteFile: %v", err)
+				}
+			},
+			wantSlug: "post",
+		},
+		{
+			name: "after replacing both files, before commit",
+			crash: func(t *testing.T, s *FileBlogStore, blog models.Blog) {
+				txn, err := s.beginBlogTxn(blog.ID.String(), "post", "post")
+				if err != nil {
+					t.Fatalf("beginBlogTxn: %v", err)
+				}
+				if err := s.saveBlog(txn, edited(blog), "post"); err != nil {
+					t.Fatalf("saveBlog: %v", err)
+				}
+			},
+			wantSlug: "post",
+		},
+		{
+			name: "after a rename and both files, before commit",
+			crash: func(t *testing.T, s *FileBlogStore, blog models.Blog) {
+				txn, err := s.beginBlogTxn(blog.ID.String(), "post", "renamed")
+				if err != nil {
+					t.Fatalf("beginBlogTxn: %v", err)
+				}
+				if err := s.saveBlog(txn, edited(blog), "renamed"); err != nil {
+					t.Fatalf("saveBlog: %v", err)
+				}
+			},
+			wantSlug: "post",
+		},
+		{
+			name: "with a half-written journal",
+			crash: func(t *testing.T, s *FileBlogStore, blog models.Blog) {
+				journalPath := filepath.Join(s.dataDir, blogJournalPrefix+blog.ID.String()+".json")
+				if err := os.WriteFile(journalPath, []byte(`{"id":"`), 0644); err != nil {
+					t.Fatalf("WriteFile: %v", err)
+				}
+			},
+			wantSlug: "post",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataDir := t.TempDir()
+			store, err := NewFileBlogStore(dataDir)
+			if err != nil {
+				t.Fatalf("NewFileBlogStore: %v", err)
+			}
+			blog, err := store.CreateBlog(models.Blog{Title: "Old title", Content: "old content", Slug: "post"})
+			if err != nil {
+				t.Fatalf("CreateBlog: %v", err)
+			}
+			oldContent, oldMetadata := blogFiles(t, store.GetBlogDir("post"))
+
+			tt.crash(t, store, blog)
+
+			// Opening the data directory again recovers it
+			recovered, err := NewFileBlogStore(dataDir)
+			if err != nil {
+				t.Fatalf("NewFileBlogStore after crash: %v", err)
+			}
+
+			content, metadata := blogFiles(t, recovered.GetBlogDir(tt.wantSlug))
+			if content != oldContent {
+				t.Errorf("content.md = %q, want %q", content, oldContent)
+			}
+			if metadata != oldMetadata {
+				t.Errorf("metadata.json = %s, want %s", metadata, oldMetadata)
+			}
+
+			blogs, err := recovered.GetAllBlogs()
+			if err != nil {
+				t.Fatalf("GetAllBlogs: %v", err)
+			}
+			if len(blogs) != 1 || blogs[0].Slug != tt.wantSlug || blogs[0].Title != "Old title" {
+				t.Errorf("blogs after recovery = %+v, want only %q with its old title", blogs, tt.wantSlug)
+			}
+			if _, err := os.Stat(recovered.GetBlogDir("renamed")); !os.IsNotExist(err) {
+				t.Errorf("renamed directory still exists after recovery")
+			}
+			if found := leftovers(t, dataDir); len(found) > 0 {
+				t.Errorf("leftover journal or temp files: %v", found)
+			}
+		})
+	}
+}
+
+func TestRecoverInterruptedBlogCreate(t *testing.T) {
+	dataDir := t.TempDir()
+	store, err := NewFileBlogStore(dataDir)
+	if err != nil {
+		t.Fatalf("NewFileBlogStore: %v", err)
+	}
+
+	// A new blog's files replace nothing, so recovery removes them
+	blog := models.Blog{ID: store.generateUUID(), Title: "New", Content: "new content", Slug: "post"}
+	txn, err := store.beginBlogTxn(blog.ID.String(), "post", "post")
+	if err != nil {
+		t.Fatalf("beginBlogTxn: %v", err)
+	}
+	if err := store.saveBlog(txn, blog, "post"); err != nil {
+		t.Fatalf("saveBlog: %v", err)
+	}
+
+	recovered, err := NewFileBlogStore(dataDir)
+	if err != nil {
+		t.Fatalf("NewFileBlogStore after crash: %v", err)
+	}
+	blogs, err := recovered.GetAllBlogs()
+	if err != nil {
+		t.Fatalf("GetAllBlogs: %v", err)
+	}
+	if len(blogs) != 0 {
+		t.Errorf("blogs after recovery = %+v, want none", blogs)
+	}
+	if found := leftovers(t, dataDir); len(found) > 0 {
+		t.Errorf("leftover journal or temp files: %v", found)
+	}
+}
+
+func TestRecoverKeepsCommittedUpdate(t *testing.T) {
+	dataDir := t.TempDir()
+	store, err := NewFileBlogStore(dataDir)
+	if err != nil {
+		t.Fatalf("NewFileBlogStore: %v", err)
+	}
+	if _, err := store.CreateBlog(models.Blog{Title: "Old title", Content: "old content", Slug: "post"}); err != nil {
+		t.Fatalf("CreateBlog: %v", err)
+	}
+	content := "new content"
+	if _, err := store.UpdateBlogBySlug("post", models.UpdateBlogRequest{Content: &content}); err != nil {
+		t.Fatalf("UpdateBlogBySlug: %v", err)
+	}
+
+	// A crash after the journal is removed but before the backups are
+	// leaves backups behind, which recovery must not restore
+	backups := []string{
+		backupPath(store.getBlogContentPath("post")),
+		backupPath(filepath.Join(store.getBlogRevisionsDir("post"), "1.json")),
+	}
+	for _, backup := range backups {
+		if err := os.WriteFile(backup, []byte("old"), 0644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	recovered, err := NewFileBlogStore(dataDir)
+	if err != nil {
+		t.Fatalf("NewFileBlogStore after crash: %v", err)
+	}
+	blog, err := recovered.GetBlogBySlug("post")
+	if err != nil {
+		t.Fatalf("GetBlogBySlug: %v", err)
+	}
+	if blog.Content != content {
+		t.Errorf("content = %q, want %q", blog.Content, content)
+	}
+	if found := leftovers(t, dataDir); len(found) > 0 {
+		t.Errorf("leftover journal or temp files: %v", found)
+	}
+}
+
+func TestBlogTxnRollback(t *testing.T) {
+	dataDir := t.TempDir()
+	store, err := NewFileBlogStore(dataDir)
+	if err != nil {
+		t.Fatalf("NewFileBlogStore: %v", err)
+	}
+	blog, err := store.CreateBlog(models.Blog{Title: "Old title", Content: "old content", Slug: "post"})
+	if err != nil {
+		t.Fatalf("CreateBlog: %v", err)
+	}
+	oldContent, oldMetadata := blogFiles(t, store.GetBlogDir("post"))
+
+	txn, err := store.beginBlogTxn(blog.ID.String(), "post", "renamed")
+	if err != nil {
+		t.Fatalf("beginBlogTxn: %v", err)
+	}
+	if err := store.saveBlog(txn, edited(blog), "renamed"); err != nil {
+		t.Fatalf("saveBlog: %v", err)
+	}
+	txn.rollback()
+
+	content, metadata := blogFiles(t, store.GetBlogDir("post"))
+	if content != oldContent || metadata != oldMetadata {
+		t.Errorf("files after rollback = %q, %s; want %q, %s", content, metadata, oldContent, oldMetadata)
+	}
+	if _, err := os.Stat(store.GetBlogDir("renamed")); !os.IsNotExist(err) {
+		t.Errorf("renamed directory still exists after rollback")
+	}
+	if found := leftovers(t, dataDir); len(found) > 0 {
+		t.Errorf("leftover journal or temp files: %v", found)
+	}
+}
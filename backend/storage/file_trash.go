@@ -0,0 +1,171 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"go-react-backend/models"
+
+	"github.com/google/uuid"
+)
+
+// trashDirName is the hidden directory under the data directory that holds
+// deleted blogs, one directory per blog ID
+const trashDirName = ".trash"
+
+// trashInfoName is the file inside a trashed blog directory that records
+// when and from where it was deleted
+const trashInfoName = ".deleted.json"
+
+// trashInfo is the content of a trashInfoName file
+type trashInfo struct {
+	Slug      string    `json:"slug"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// getTrashDir returns the directory holding a trashed blog
+func (s *FileBlogStore) getTrashDir(id uuid.UUID) string {
+	return filepath.Join(s.dataDir, trashDirName, id.String())
+}
+
+// moveToTrash moves a blog directory into the trash. The caller must hold
+// the write lock.
+func (s *FileBlogStore) moveToTrash(blog models.Blog) error {
+	trashRoot := filepath.Join(s.dataDir, trashDirName)
+	if err := os.MkdirAll(trashRoot, 0755); err != nil {
+		return fmt.Errorf("failed to create trash directory: %w", err)
+	}
+
+	// A leftover entry for the same blog would block the rename
+	trashDir := s.getTrashDir(blog.ID)
+	if err := os.RemoveAll(trashDir); err != nil {
+		return fmt.Errorf("failed to clear old trash entry: %w", err)
+	}
+
+	if err := os.Rename(s.GetBlogDir(blog.Slug), trashDir); err != nil {
+		return fmt.Errorf("failed to move blog to trash: %w", err)
+	}
+
+	infoData, err := json.MarshalIndent(trashInfo{Slug: blog.Slug, DeletedAt: time.Now()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal trash info: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(trashDir, trashInfoName), infoData, 0644); err != nil {
+		return fmt.Errorf("failed to write trash info: %w", err)
+	}
+
+	return syncDir(s.dataDir)
+}
+
+// loadTrash loads every blog in the trash, most recently deleted first. The
+// caller must hold the lock.
+func (s *FileBlogStore) loadTrash() ([]models.TrashedBlog, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dataDir, trashDirName))
+	if os.IsNotExist(err) {
+		return []models.TrashedBlog{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+
+	trashed := []models.TrashedBlog{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		trashDir := filepath.Join(s.dataDir, trashDirName, entry.Name())
+
+		blog, err := loadBlogDir(trashDir)
+		if err != nil {
+			continue // Skip entries that aren't valid blogs
+		}
+
+		// Entries without readable info fall back to the directory's mtime
+		var info trashInfo
+		if infoData, err := os.ReadFile(filepath.Join(trashDir, trashInfoName)); err == nil {
+			json.Unmarshal(infoData, &info)
+		}
+		if info.DeletedAt.IsZero() {
+			if stat, err := os.Stat(trashDir); err == nil {
+				info.DeletedAt = stat.ModTime()
+			}
+		}
+
+		trashed = append(trashed, models.TrashedBlog{Blog: blog, DeletedAt: info.DeletedAt})
+	}
+
+	sort.Slice(trashed, func(i, j int) bool {
+		return trashed[i].DeletedAt.After(trashed[j].DeletedAt)
+	})
+
+	return trashed, nil
+}
+
+// ListTrash implements the TrashStore interface
+func (s *FileBlogStore) ListTrash() ([]models.TrashedBlog, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.loadTrash()
+}
+
+// RestoreTrashedBlog implements the TrashStore interface. The blog returns
+// under the slug it was deleted with, which must not have been reused.
+func (s *FileBlogStore) RestoreTrashedBlog(id uuid.UUID) (*models.Blog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trashDir := s.getTrashDir(id)
+	blog, err := loadBlogDir(trashDir)
+	if err != nil {
+		return nil, errors.New("blog not found in trash")
+	}
+
+	exists, err := s.blogExists(blog.Slug)
+	if err != nil {
+		return nil, err
+	}
+	if _, statErr := os.Stat(s.GetBlogDir(blog.Slug)); exists || statErr == nil {
+		return nil, models.ErrSlugExists
+	}
+
+	if err := os.Rename(trashDir, s.GetBlogDir(blog.Slug)); err != nil {
+		return nil, fmt.Errorf("failed to restore blog from trash: %w", err)
+	}
+	os.Remove(filepath.Join(s.GetBlogDir(blog.Slug), trashInfoName))
+
+	if err := syncDir(s.dataDir); err != nil {
+		return nil, fmt.Errorf("failed to sync data directory: %w", err)
+	}
+
+	return &blog, nil
+}
+
+// PurgeTrash implements the TrashStore interface, permanently removing
+// blogs deleted before the given time
+func (s *FileBlogStore) PurgeTrash(deletedBefore time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trashed, err := s.loadTrash()
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, item := range trashed {
+		if !item.DeletedAt.Before(deletedBefore) {
+			continue
+		}
+		if err := os.RemoveAll(s.getTrashDir(item.Blog.ID)); err != nil {
+			return purged, fmt.Errorf("failed to purge blog %s: %w", item.Blog.ID, err)
+		}
+		purged++
+	}
+
+	return purged, nil
+}
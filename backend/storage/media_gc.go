@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"go-react-backend/models"
+)
+
+// CollectMediaPeriodically deletes library assets that no post has used
+// since their grace period ran out, checking once immediately and then every
+// interval until done is closed. Run it in its own goroutine.
+func CollectMediaPeriodically(library models.MediaLibrary, interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := library.CollectGarbage(time.Now().Add(-models.MediaGCGracePeriod))
+		if err != nil {
+			fmt.Printf("❌ Failed to collect unused media: %v\n", err)
+		} else if deleted > 0 {
+			fmt.Printf("🗑️ Deleted %d unused media asset(s)\n", deleted)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-react-backend/models"
+)
+
+// testStore is what both backends implement
+type testStore interface {
+	models.BlogStore
+	models.MediaLibrary
+	models.RevisionStore
+	models.TrashStore
+}
+
+// newTestStores returns an empty store of each backend, by name
+func newTestStores(t *testing.T) map[string]testStore {
+	t.Helper()
+	fileStore, err := NewFileBlogStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileBlogStore: %v", err)
+	}
+	sqliteStore, err := NewSQLiteBlogStore(filepath.Join(t.TempDir(), "blogs.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteBlogStore: %v", err)
+	}
+	t.Cleanup(func() { sqliteStore.Close() })
+
+	return map[string]testStore{"file": fileStore, "sqlite": sqliteStore}
+}
+
+// saveTestAsset stores a one-file asset whose hash is c repeated
+func saveTestAsset(t *testing.T, library models.MediaLibrary, c string) string {
+	t.Helper()
+	hash := strings.Repeat(c, 64)
+	asset := models.MediaAsset{Hash: hash, Filename: "image.jpg", MimeType: "image/jpeg", Width: 1, Height: 1}
+	if _, err := library.SaveAsset(asset, map[string][]byte{"image.jpg": []byte("jpeg " + c)}); err != nil {
+		t.Fatalf("SaveAsset: %v", err)
+	}
+	return hash
+}
+
+func TestCollectGarbageKeepsRevisionMedia(t *testing.T) {
+	for name, store := range newTestStores(t) {
+		t.Run(name, func(t *testing.T) {
+			used := saveTestAsset(t, store, "a")
+			unused := saveTestAsset(t, store, "b")
+
+			content := "![Photo](/api/images/post/photo.jpg)"
+			_, err := store.CreateBlog(models.Blog{
+				Title:   "Post",
+				Content: content,
+				Slug:    "post",
+				Media:   []models.MediaItem{{Hash: used, Filename: "photo.jpg", MimeType: "image/jpeg"}},
+			})
+			if err != nil {
+				t.Fatalf("CreateBlog: %v", err)
+			}
+
+			// Revision 1 keeps the photo; the live post then drops it
+			rewritten := "No photos here"
+			if _, err := store.UpdateBlogBySlug("post", models.UpdateBlogRequest{Content: &rewritten}); err != nil {
+				t.Fatalf("UpdateBlogBySlug: %v", err)
+			}
+			if _, err := store.UpdateBlogBySlug("post", models.UpdateBlogRequest{Media: &[]models.MediaItem{}}); err != nil {
+				t.Fatalf("UpdateBlogBySlug: %v", err)
+			}
+
+			deleted, err := store.CollectGarbage(time.Now().Add(time.Hour))
+			if err != nil {
+				t.Fatalf("CollectGarbage: %v", err)
+			}
+			if deleted != 1 {
+				t.Errorf("CollectGarbage deleted %d assets, want 1", deleted)
+			}
+			if _, err := store.GetAsset(unused); err == nil {
+				t.Errorf("unused asset survived garbage collection")
+			}
+
+			restored, err := store.RestoreRevision("post", 1)
+			if err != nil {
+				t.Fatalf("RestoreRevision: %v", err)
+			}
+			if restored.Content != content {
+				t.Errorf("restored content = %q, want %q", restored.Content, content)
+			}
+			if len(restored.Media) != 1 || restored.Media[0].Filename != "photo.jpg" || restored.Media[0].Hash != used {
+				t.Fatalf("restored media = %+v, want photo.jpg from %s", restored.Media, used)
+			}
+			if _, err := store.GetAssetFile(used, "image.jpg"); err != nil {
+				t.Errorf("GetAssetFile after restore: %v", err)
+			}
+		})
+	}
+}
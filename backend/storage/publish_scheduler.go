@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go-react-backend/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// schedulerMaxSleep bounds how long the scheduler waits between checks,
+	// so clock adjustments can't delay a transition indefinitely
+	schedulerMaxSleep = time.Hour
+
+	// schedulerRetryDelay is how long the scheduler waits before retrying
+	// a failed check or transition
+	schedulerRetryDelay = time.Minute
+)
+
+// PublishScheduler publishes and unpublishes blogs when their publish_at and
+// unpublish_at times arrive. Transitions are written through the store it is
+// given, so with a CachedBlogStore that publishes changes every transition
+// fires a change event at the moment it happens.
+type PublishScheduler struct {
+	store models.BlogStore
+	wake  chan struct{}
+
+	// writeLock is held while a transition is checked and written, so it
+	// can't slip in between another writer's version check and its write
+	writeLock sync.Locker
+}
+
+// NewPublishScheduler creates a scheduler for the blogs in store that holds
+// writeLock, the lock API writes take, while applying each transition
+func NewPublishScheduler(store models.BlogStore, writeLock sync.Locker) *PublishScheduler {
+	return &PublishScheduler{store: store, wake: make(chan struct{}, 1), writeLock: writeLock}
+}
+
+// Wake makes the scheduler look at the blogs' schedules again. Call it when
+// blogs change, e.g. from a ChangeEvent subscriber.
+func (s *PublishScheduler) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run applies due transitions immediately and then whenever the next one
+// comes due or Wake is called, until done is closed. Run it in its own
+// goroutine.
+func (s *PublishScheduler) Run(done <-chan struct{}) {
+	for {
+		wait := schedulerMaxSleep
+		if next := s.applyDue(time.Now()); !next.IsZero() {
+			if until := time.Until(next); until < wait {
+				wait = until
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-s.wake:
+			timer.Stop()
+		case <-done:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// applyDue applies every transition due at now and returns when the next one
+// is due, or the zero time if none is scheduled
+func (s *PublishScheduler) applyDue(now time.Time) time.Time {
+	blogs, err := s.store.GetAllBlogs()
+	if err != nil {
+		fmt.Printf("❌ Failed to check publishing schedules: %v\n", err)
+		return now.Add(schedulerRetryDelay)
+	}
+
+	var next time.Time
+	for _, blog := range blogs {
+		transitionAt := blog.NextTransition()
+		if _, due := blog.DueTransition(now); due {
+			updated, err := s.applyTransition(blog.ID, now)
+			switch {
+			case err != nil:
+				fmt.Printf("❌ Failed to apply publishing schedule for blog %s: %v\n", blog.Slug, err)
+				transitionAt = now.Add(schedulerRetryDelay)
+			case updated == nil:
+				// Deleted or rescheduled in the meantime; its change event
+				// wakes the scheduler again
+				transitionAt = time.Time{}
+			default:
+				if updated.Published {
+					fmt.Printf("⏰ Published scheduled blog %s\n", updated.Slug)
+				} else {
+					fmt.Printf("⏰ Unpublished scheduled blog %s\n", updated.Slug)
+				}
+				transitionAt = updated.NextTransition()
+			}
+		}
+
+		if !transitionAt.IsZero() && (next.IsZero() || transitionAt.Before(next)) {
+			next = transitionAt
+		}
+	}
+	return next
+}
+
+// applyTransition writes the transition due at now for the blog with id,
+// reading it again under the write lock in case it changed since it was
+// listed. It returns the updated blog, or nil if no transition is due any
+// more.
+func (s *PublishScheduler) applyTransition(id uuid.UUID, now time.Time) (*models.Blog, error) {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+
+	blog, err := s.store.GetBlogByID(id)
+	if err != nil {
+		if err.Error() == "blog not found" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	updates, due := blog.DueTransition(now)
+	if !due {
+		return nil, nil
+	}
+	return s.store.UpdateBlogBySlug(blog.Slug, updates)
+}
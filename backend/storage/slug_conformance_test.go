@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"go-react-backend/models"
+)
+
+// TestSlugConflicts checks that both backends agree on which writes clash
+// with another blog's slug
+func TestSlugConflicts(t *testing.T) {
+	create := func(store testStore, title, slug string) (models.Blog, error) {
+		return store.CreateBlog(models.Blog{Title: title, Content: "Hello", Slug: slug})
+	}
+
+	tests := []struct {
+		name    string
+		run     func(t *testing.T, store testStore) error
+		wantErr error
+	}{
+		{
+			name: "new slug",
+			run: func(t *testing.T, store testStore) error {
+				_, err := create(store, "Hello", "")
+				return err
+			},
+		},
+		{
+			name: "same explicit slug",
+			run: func(t *testing.T, store testStore) error {
+				if _, err := create(store, "First", "post"); err != nil {
+					t.Fatalf("CreateBlog: %v", err)
+				}
+				_, err := create(store, "Second", "post")
+
+				// The first blog must be left as it was
+				if blog, getErr := store.GetBlogBySlug("post"); getErr != nil || blog.Title != "First" {
+					t.Errorf("after the clash GetBlogBySlug = %+v, %v; want the first blog", blog, getErr)
+				}
+				return err
+			},
+			wantErr: models.ErrSlugExists,
+		},
+		{
+			name: "same slug from the title",
+			run: func(t *testing.T, store testStore) error {
+				if _, err := create(store, "Hello World", ""); err != nil {
+					t.Fatalf("CreateBlog: %v", err)
+				}
+				_, err := create(store, "Hello, World!", "")
+				return err
+			},
+			wantErr: models.ErrSlugExists,
+		},
+		{
+			name: "slug of a trashed blog",
+			run: func(t *testing.T, store testStore) error {
+				if _, err := create(store, "First", "post"); err != nil {
+					t.Fatalf("CreateBlog: %v", err)
+				}
+				if err := store.DeleteBlogBySlug("post"); err != nil {
+					t.Fatalf("DeleteBlogBySlug: %v", err)
+				}
+				_, err := create(store, "Second", "post")
+				return err
+			},
+		},
+		{
+			name: "rename onto a taken slug",
+			run: func(t *testing.T, store testStore) error {
+				if _, err := create(store, "First", "first"); err != nil {
+					t.Fatalf("CreateBlog: %v", err)
+				}
+				if _, err := create(store, "Second", "second"); err != nil {
+					t.Fatalf("CreateBlog: %v", err)
+				}
+				taken := "first"
+				_, err := store.UpdateBlogBySlug("second", models.UpdateBlogRequest{Slug: &taken})
+				return err
+			},
+			wantErr: models.ErrSlugExists,
+		},
+		{
+			name: "restore from the trash onto a reused slug",
+			run: func(t *testing.T, store testStore) error {
+				trashed, err := create(store, "First", "post")
+				if err != nil {
+					t.Fatalf("CreateBlog: %v", err)
+				}
+				if err := store.DeleteBlogBySlug("post"); err != nil {
+					t.Fatalf("DeleteBlogBySlug: %v", err)
+				}
+				if _, err := create(store, "Second", "post"); err != nil {
+					t.Fatalf("CreateBlog: %v", err)
+				}
+				_, err = store.RestoreTrashedBlog(trashed.ID)
+				return err
+			},
+			wantErr: models.ErrSlugExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, store := range newTestStores(t) {
+				t.Run(name, func(t *testing.T) {
+					err := tt.run(t, store)
+					if tt.wantErr == nil && err != nil {
+						t.Fatalf("got error %v, want none", err)
+					}
+					if !errors.Is(err, tt.wantErr) {
+						t.Fatalf("got error %v, want %v", err, tt.wantErr)
+					}
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-react-backend/models"
+
+	"github.com/google/uuid"
+)
+
+// assetColumns lists the media_assets columns in the order scanAsset expects
+// them, followed by the number of blogs using the asset
+const assetColumns = `hash, filename, mime_type, width, height, variants, thumbnail,
+	original, placeholder, color, size, created, uploaded, (
+		SELECT COUNT(DISTINCT b.id) FROM blogs b, json_each(b.media) m
+		WHERE json_extract(m.value, '$.hash') = media_assets.hash
+	)`
+
+// usedHashesQuery selects the hash of every library asset a blog, live or in
+// the trash, or one of its revisions uses
+const usedHashesQuery = `SELECT json_extract(m.value, '$.hash') FROM blogs b, json_each(b.media) m
+	WHERE json_extract(m.value, '$.hash') != ''
+	UNION SELECT json_extract(m.value, '$.hash') FROM blog_revisions r, json_each(r.previous, '$.media') m
+	WHERE json_extract(m.value, '$.hash') != ''`
+
+// scanAsset reads an asset row selected with assetColumns
+func scanAsset(row rowScanner) (models.MediaAsset, error) {
+	var asset models.MediaAsset
+	var variants string
+	var created, uploaded int64
+
+	err := row.Scan(&asset.Hash, &asset.Filename, &asset.MimeType, &asset.Width, &asset.Height,
+		&variants, &asset.Thumbnail, &asset.Original, &asset.Placeholder, &asset.Color,
+		&asset.Size, &created, &uploaded, &asset.RefCount)
+	if err != nil {
+		return models.MediaAsset{}, err
+	}
+
+	if err := json.Unmarshal([]byte(variants), &asset.Variants); err != nil {
+		return models.MediaAsset{}, fmt.Errorf("invalid variants for asset %s: %w", asset.Hash, err)
+	}
+	asset.Created = time.Unix(0, created)
+	asset.Uploaded = time.Unix(0, uploaded)
+
+	return asset, nil
+}
+
+// getAsset loads an asset using the given query runner (db or tx)
+func getAsset(q queryRower, hash string) (*models.MediaAsset, error) {
+	asset, err := scanAsset(q.QueryRow("SELECT "+assetColumns+" FROM media_assets WHERE hash = ?", hash))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("asset not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read asset: %w", err)
+	}
+	return &asset, nil
+}
+
+// SaveAsset implements the MediaLibrary interface
+func (s *SQLiteBlogStore) SaveAsset(asset models.MediaAsset, files map[string][]byte) (*models.MediaAsset, error) {
+	if !models.ValidMediaHash(asset.Hash) {
+		return nil, fmt.Errorf("invalid asset hash %q", asset.Hash)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	existing, err := getAsset(tx, asset.Hash)
+	if err == nil {
+		if _, err := tx.Exec("UPDATE media_assets SET uploaded = ? WHERE hash = ?", now.UnixNano(), asset.Hash); err != nil {
+			return nil, fmt.Errorf("failed to update asset: %w", err)
+		}
+		existing.Uploaded = now
+		return existing, tx.Commit()
+	}
+	if err.Error() != "asset not found" {
+		return nil, err
+	}
+
+	asset.Size = 0
+	for _, filename := range asset.Files() {
+		data, ok := files[filename]
+		if !ok {
+			return nil, fmt.Errorf("missing asset file %q", filename)
+		}
+		asset.Size += int64(len(data))
+	}
+	asset.Created = now
+	asset.Uploaded = now
+
+	_, err = tx.Exec(`INSERT INTO media_assets (hash, filename, mime_type, width, height,
+		variants, thumbnail, original, placeholder, color, size, created, uploaded)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		asset.Hash, asset.Filename, asset.MimeType, asset.Width, asset.Height,
+		marshalImageVariants(asset.Variants), asset.Thumbnail, asset.Original,
+		asset.Placeholder, asset.Color, asset.Size,
+		now.UnixNano(), now.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert asset: %w", err)
+	}
+	for _, filename := range asset.Files() {
+		_, err := tx.Exec("INSERT INTO media_files (hash, filename, data) VALUES (?, ?, ?)",
+			asset.Hash, filename, files[filename])
+		if err != nil {
+			return nil, fmt.Errorf("failed to write asset file: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit asset: %w", err)
+	}
+
+	fmt.Printf("🖼️ Stored media asset %s (%d bytes)\n", asset.Hash, asset.Size)
+	return &asset, nil
+}
+
+// GetAsset implements the MediaLibrary interface
+func (s *SQLiteBlogStore) GetAsset(hash string) (*models.MediaAsset, error) {
+	return getAsset(s.db, hash)
+}
+
+// GetAssetFile implements the MediaLibrary interface
+func (s *SQLiteBlogStore) GetAssetFile(hash string, filename string) ([]byte, error) {
+	var data []byte
+	err := s.db.QueryRow("SELECT data FROM media_files WHERE hash = ? AND filename = ?", hash, filename).Scan(&data)
+	if err == sql.ErrNoRows {
+		if _, err := getAsset(s.db, hash); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("image not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read asset file: %w", err)
+	}
+	return data, nil
+}
+
+// ListAssets implements the MediaLibrary interface
+func (s *SQLiteBlogStore) ListAssets() ([]models.MediaAsset, error) {
+	rows, err := s.db.Query("SELECT " + assetColumns + " FROM media_assets ORDER BY created DESC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query media library: %w", err)
+	}
+	defer rows.Close()
+
+	assets := []models.MediaAsset{}
+	for rows.Next() {
+		asset, err := scanAsset(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read asset: %w", err)
+		}
+		assets = append(assets, asset)
+	}
+
+	return assets, rows.Err()
+}
+
+// AssetUsage implements the MediaLibrary interface
+func (s *SQLiteBlogStore) AssetUsage(hash string) ([]models.MediaUsage, error) {
+	if _, err := getAsset(s.db, hash); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`SELECT b.id, b.slug, b.title, json_extract(m.value, '$.filename'),
+		b.deleted_at IS NOT NULL
+		FROM blogs b, json_each(b.media) m
+		WHERE json_extract(m.value, '$.hash') = ?
+		ORDER BY b.created DESC`, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query asset usage: %w", err)
+	}
+	defer rows.Close()
+
+	usage := []models.MediaUsage{}
+	for rows.Next() {
+		var use models.MediaUsage
+		var id string
+		if err := rows.Scan(&id, &use.Slug, &use.Title, &use.Filename, &use.Trashed); err != nil {
+			return nil, fmt.Errorf("failed to read asset usage: %w", err)
+		}
+		use.BlogID, err = uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blog id %q: %w", id, err)
+		}
+		usage = append(usage, use)
+	}
+
+	return usage, rows.Err()
+}
+
+// CollectGarbage implements the MediaLibrary interface. Asset files go with
+// their asset through the foreign key.
+func (s *SQLiteBlogStore) CollectGarbage(uploadedBefore time.Time) (int, error) {
+	result, err := s.db.Exec(`DELETE FROM media_assets WHERE uploaded < ? AND hash NOT IN (`+usedHashesQuery+`)`,
+		uploadedBefore.UnixNano())
+	if err != nil {
+		return 0, fmt.Errorf("failed to collect media garbage: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to collect media garbage: %w", err)
+	}
+
+	return int(affected), nil
+}
@@ -0,0 +1,735 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go-react-backend/models"
+
+	"github.com/google/uuid"
+	_ "modernc.org/sqlite" // Pure-Go SQLite driver, registered as "sqlite"
+)
+
+// sqliteMigrations holds the schema changes for the SQLite store. Each entry is
+// applied once, in order, and tracked through PRAGMA user_version, so new
+// migrations must only ever be appended.
+var sqliteMigrations = []string{
+	`CREATE TABLE blogs (
+		id               TEXT PRIMARY KEY,
+		slug             TEXT NOT NULL,
+		title            TEXT NOT NULL,
+		content          TEXT NOT NULL,
+		image            TEXT NOT NULL DEFAULT '',
+		author_name      TEXT NOT NULL DEFAULT '',
+		author_username  TEXT NOT NULL DEFAULT '',
+		meta_name        TEXT NOT NULL DEFAULT '',
+		meta_description TEXT NOT NULL DEFAULT '',
+		created          INTEGER NOT NULL,
+		updated          INTEGER NOT NULL,
+		published        INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE UNIQUE INDEX idx_blogs_slug ON blogs(slug);
+	CREATE INDEX idx_blogs_created ON blogs(created);
+	CREATE INDEX idx_blogs_published ON blogs(published, created);
+	CREATE TABLE blog_images (
+		blog_id  TEXT NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+		filename TEXT NOT NULL,
+		data     BLOB NOT NULL,
+		PRIMARY KEY (blog_id, filename)
+	);`,
+	`CREATE TABLE blog_revisions (
+		blog_id        TEXT NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+		revision_id    INTEGER NOT NULL,
+		created        INTEGER NOT NULL,
+		changed_fields TEXT NOT NULL,
+		previous       TEXT NOT NULL,
+		PRIMARY KEY (blog_id, revision_id)
+	);`,
+	`ALTER TABLE blogs ADD COLUMN deleted_at INTEGER;
+	DROP INDEX idx_blogs_slug;
+	CREATE UNIQUE INDEX idx_blogs_slug ON blogs(slug) WHERE deleted_at IS NULL;
+	CREATE INDEX idx_blogs_deleted_at ON blogs(deleted_at) WHERE deleted_at IS NOT NULL;`,
+	`CREATE TABLE slug_history (
+		blog_id    TEXT NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+		slug       TEXT NOT NULL,
+		changed_at INTEGER NOT NULL,
+		PRIMARY KEY (blog_id, slug)
+	);
+	CREATE INDEX idx_slug_history_slug ON slug_history(slug, changed_at);`,
+	`ALTER TABLE blogs ADD COLUMN tags TEXT NOT NULL DEFAULT '[]';
+	ALTER TABLE blogs ADD COLUMN category TEXT NOT NULL DEFAULT '';
+	CREATE INDEX idx_blogs_category ON blogs(category COLLATE NOCASE);`,
+	`ALTER TABLE blogs ADD COLUMN publish_at INTEGER;
+	ALTER TABLE blogs ADD COLUMN unpublish_at INTEGER;`,
+	`ALTER TABLE blogs ADD COLUMN image_variants TEXT NOT NULL DEFAULT '[]';
+	ALTER TABLE blogs ADD COLUMN image_thumbnail TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE blogs ADD COLUMN image_mime_type TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE blogs ADD COLUMN media TEXT NOT NULL DEFAULT '[]';`,
+	`CREATE TABLE media_assets (
+		hash      TEXT PRIMARY KEY,
+		filename  TEXT NOT NULL,
+		mime_type TEXT NOT NULL,
+		width     INTEGER NOT NULL,
+		height    INTEGER NOT NULL,
+		variants  TEXT NOT NULL DEFAULT '[]',
+		thumbnail TEXT NOT NULL DEFAULT '',
+		size      INTEGER NOT NULL,
+		created   INTEGER NOT NULL,
+		uploaded  INTEGER NOT NULL
+	);
+	CREATE TABLE media_files (
+		hash     TEXT NOT NULL REFERENCES media_assets(hash) ON DELETE CASCADE,
+		filename TEXT NOT NULL,
+		data     BLOB NOT NULL,
+		PRIMARY KEY (hash, filename)
+	);`,
+	`ALTER TABLE media_assets ADD COLUMN original TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE media_assets ADD COLUMN placeholder TEXT NOT NULL DEFAULT '';
+	ALTER TABLE media_assets ADD COLUMN color TEXT NOT NULL DEFAULT '';`,
+}
+
+// blogColumns lists the blog columns in the order scanBlog expects them
+const blogColumns = `id, slug, title, content, image, author_name, author_username,
+	meta_name, meta_description, created, updated, published, tags, category,
+	publish_at, unpublish_at, image_variants, image_thumbnail, image_mime_type, media`
+
+// SQLiteBlogStore implements BlogStore on top of an embedded SQLite database
+type SQLiteBlogStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteBlogStore opens (or creates) the SQLite database at dbPath and
+// brings its schema up to date
+func NewSQLiteBlogStore(dbPath string) (*SQLiteBlogStore, error) {
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	dsn := "file:" + dbPath + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// SQLite only supports a single writer; serialising connections avoids
+	// "database is locked" errors under concurrent requests
+	db.SetMaxOpenConns(1)
+
+	store := &SQLiteBlogStore{db: db}
+	if err := store.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// Close closes the underlying database
+func (s *SQLiteBlogStore) Close() error {
+	return s.db.Close()
+}
+
+// migrate applies any migrations the database has not seen yet
+func (s *SQLiteBlogStore) migrate() error {
+	var version int
+	if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for i := version; i < len(sqliteMigrations); i++ {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to begin migration: %w", err)
+		}
+		if _, err := tx.Exec(sqliteMigrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply migration %d: %w", i+1, err)
+		}
+		// PRAGMA does not accept bound parameters
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record migration %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// queryRower is satisfied by both *sql.DB and *sql.Tx
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// scanBlog reads a blog row selected with blogColumns
+func scanBlog(row rowScanner) (models.Blog, error) {
+	var blog models.Blog
+	var id string
+	var created, updated int64
+	var tags, imageVariants, media string
+	var publishAt, unpublishAt sql.NullInt64
+
+	err := row.Scan(&id, &blog.Slug, &blog.Title, &blog.Content, &blog.Image,
+		&blog.AuthorName, &blog.AuthorUsername, &blog.MetaName, &blog.MetaDescription,
+		&created, &updated, &blog.Published, &tags, &blog.Category,
+		&publishAt, &unpublishAt, &imageVariants, &blog.ImageThumbnail, &blog.ImageMimeType,
+		&media)
+	if err != nil {
+		return models.Blog{}, err
+	}
+
+	if err := json.Unmarshal([]byte(tags), &blog.Tags); err != nil {
+		return models.Blog{}, fmt.Errorf("invalid tags for blog %s: %w", id, err)
+	}
+	if err := json.Unmarshal([]byte(imageVariants), &blog.ImageVariants); err != nil {
+		return models.Blog{}, fmt.Errorf("invalid image variants for blog %s: %w", id, err)
+	}
+	if err := json.Unmarshal([]byte(media), &blog.Media); err != nil {
+		return models.Blog{}, fmt.Errorf("invalid media for blog %s: %w", id, err)
+	}
+
+	blog.ID, err = uuid.Parse(id)
+	if err != nil {
+		return models.Blog{}, fmt.Errorf("invalid blog id %q: %w", id, err)
+	}
+	blog.Created = time.Unix(0, created)
+	blog.Updated = time.Unix(0, updated)
+	blog.PublishAt = scanTime(publishAt)
+	blog.UnpublishAt = scanTime(unpublishAt)
+
+	return blog, nil
+}
+
+// scanTime converts a nullable nanosecond timestamp column
+func scanTime(value sql.NullInt64) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	t := time.Unix(0, value.Int64).UTC()
+	return &t
+}
+
+// nullTime converts an optional time for a nullable timestamp column
+func nullTime(t *time.Time) sql.NullInt64 {
+	if t == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: t.UnixNano(), Valid: true}
+}
+
+// marshalTags encodes tags for the tags column as a JSON array
+func marshalTags(tags []string) string {
+	data, _ := json.Marshal(models.NormalizeTags(tags))
+	return string(data)
+}
+
+// marshalImageVariants encodes image variants for the image_variants column
+// as a JSON array
+func marshalImageVariants(variants []models.ImageVariant) string {
+	data, _ := json.Marshal(imageVariantsOrEmpty(variants))
+	return string(data)
+}
+
+// marshalMedia encodes a media collection for the media column as a JSON
+// array
+func marshalMedia(media []models.MediaItem) string {
+	data, _ := json.Marshal(mediaOrEmpty(media))
+	return string(data)
+}
+
+// isUniqueViolation reports whether err is a SQLite UNIQUE constraint failure
+func isUniqueViolation(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+// getBlogBySlug loads a blog using the given query runner (db or tx)
+func getBlogBySlug(q queryRower, slug string) (*models.Blog, error) {
+	row := q.QueryRow("SELECT "+blogColumns+" FROM blogs WHERE slug = ? AND deleted_at IS NULL", slug)
+	blog, err := scanBlog(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("blog not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load blog: %w", err)
+	}
+	return &blog, nil
+}
+
+// Interface implementation methods
+func (s *SQLiteBlogStore) GetAllBlogs() ([]models.Blog, error) {
+	rows, err := s.db.Query("SELECT " + blogColumns + " FROM blogs WHERE deleted_at IS NULL ORDER BY created DESC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blogs: %w", err)
+	}
+	defer rows.Close()
+
+	var blogs []models.Blog
+	for rows.Next() {
+		blog, err := scanBlog(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read blog: %w", err)
+		}
+		blogs = append(blogs, blog)
+	}
+
+	return blogs, rows.Err()
+}
+
+func (s *SQLiteBlogStore) GetBlogBySlug(slug string) (*models.Blog, error) {
+	return getBlogBySlug(s.db, slug)
+}
+
+func (s *SQLiteBlogStore) GetBlogByID(id uuid.UUID) (*models.Blog, error) {
+	row := s.db.QueryRow("SELECT "+blogColumns+" FROM blogs WHERE id = ? AND deleted_at IS NULL", id.String())
+	blog, err := scanBlog(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("blog not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load blog: %w", err)
+	}
+	return &blog, nil
+}
+
+func (s *SQLiteBlogStore) CreateBlog(blog models.Blog) (models.Blog, error) {
+	blog.ID = uuid.New()
+
+	now := time.Now()
+	blog.Created = now
+	blog.Updated = now
+
+	// Set default metadata if not provided
+	applyBlogDefaults(&blog)
+
+	_, err := s.db.Exec(`INSERT INTO blogs (`+blogColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		blog.ID.String(), blog.Slug, blog.Title, blog.Content, blog.Image,
+		blog.AuthorName, blog.AuthorUsername, blog.MetaName, blog.MetaDescription,
+		blog.Created.UnixNano(), blog.Updated.UnixNano(), blog.Published,
+		marshalTags(blog.Tags), blog.Category,
+		nullTime(blog.PublishAt), nullTime(blog.UnpublishAt),
+		marshalImageVariants(blog.ImageVariants), blog.ImageThumbnail, blog.ImageMimeType,
+		marshalMedia(blog.Media))
+	if isUniqueViolation(err) {
+		return models.Blog{}, models.ErrSlugExists
+	}
+	if err != nil {
+		return models.Blog{}, fmt.Errorf("failed to insert blog: %w", err)
+	}
+
+	return blog, nil
+}
+
+func (s *SQLiteBlogStore) UpdateBlogBySlug(slug string, updates models.UpdateBlogRequest) (*models.Blog, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	updatedBlog, err := updateBlogBySlug(tx, slug, updates)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit update: %w", err)
+	}
+
+	return updatedBlog, nil
+}
+
+// updateBlogBySlug applies updates to a blog inside tx and records the
+// previous version as a revision
+func updateBlogBySlug(tx *sql.Tx, slug string, updates models.UpdateBlogRequest) (*models.Blog, error) {
+	existingBlog, err := getBlogBySlug(tx, slug)
+	if err != nil {
+		return nil, err
+	}
+	previous := *existingBlog
+
+	// Apply updates
+	if updates.Title != nil {
+		existingBlog.Title = *updates.Title
+	}
+	if updates.Content != nil {
+		existingBlog.Content = *updates.Content
+	}
+	if updates.Image != nil {
+		existingBlog.Image = *updates.Image
+	}
+	if updates.ImageVariants != nil {
+		existingBlog.ImageVariants = *updates.ImageVariants
+	}
+	if updates.ImageThumbnail != nil {
+		existingBlog.ImageThumbnail = *updates.ImageThumbnail
+	}
+	if updates.ImageMimeType != nil {
+		existingBlog.ImageMimeType = *updates.ImageMimeType
+	}
+	if updates.Media != nil {
+		existingBlog.Media = *updates.Media
+	}
+
+	// Delete old image files that are being replaced by different files
+	for _, oldImage := range replacedImageFiles(previous, *existingBlog) {
+		if _, err := tx.Exec("DELETE FROM blog_images WHERE blog_id = ? AND filename = ?",
+			existingBlog.ID.String(), oldImage); err != nil {
+			return nil, fmt.Errorf("failed to delete image: %w", err)
+		}
+	}
+	if updates.AuthorName != nil {
+		existingBlog.AuthorName = *updates.AuthorName
+	}
+	if updates.AuthorUsername != nil {
+		existingBlog.AuthorUsername = *updates.AuthorUsername
+	}
+	if updates.MetaName != nil {
+		existingBlog.MetaName = *updates.MetaName
+	}
+	if updates.MetaDescription != nil {
+		existingBlog.MetaDescription = *updates.MetaDescription
+	}
+	if updates.Tags != nil {
+		existingBlog.Tags = models.NormalizeTags(*updates.Tags)
+	}
+	if updates.Category != nil {
+		existingBlog.Category = *updates.Category
+	}
+	if updates.Slug != nil {
+		existingBlog.Slug = *updates.Slug
+	}
+	if updates.Published != nil {
+		existingBlog.Published = *updates.Published
+	}
+	models.ApplySchedule(&existingBlog.PublishAt, updates.PublishAt)
+	models.ApplySchedule(&existingBlog.UnpublishAt, updates.UnpublishAt)
+	existingBlog.Updated = time.Now()
+
+	_, err = tx.Exec(`UPDATE blogs SET slug = ?, title = ?, content = ?, image = ?,
+		author_name = ?, author_username = ?, meta_name = ?, meta_description = ?,
+		updated = ?, published = ?, tags = ?, category = ?,
+		publish_at = ?, unpublish_at = ?, image_variants = ?, image_thumbnail = ?,
+		image_mime_type = ?, media = ?
+		WHERE id = ?`,
+		existingBlog.Slug, existingBlog.Title, existingBlog.Content, existingBlog.Image,
+		existingBlog.AuthorName, existingBlog.AuthorUsername, existingBlog.MetaName,
+		existingBlog.MetaDescription, existingBlog.Updated.UnixNano(), existingBlog.Published,
+		marshalTags(existingBlog.Tags), existingBlog.Category,
+		nullTime(existingBlog.PublishAt), nullTime(existingBlog.UnpublishAt),
+		marshalImageVariants(existingBlog.ImageVariants), existingBlog.ImageThumbnail,
+		existingBlog.ImageMimeType, marshalMedia(existingBlog.Media), existingBlog.ID.String())
+	if isUniqueViolation(err) {
+		return nil, models.ErrSlugExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update blog: %w", err)
+	}
+
+	// Remember the old slug so links to it can be redirected
+	if previous.Slug != existingBlog.Slug {
+		_, err = tx.Exec(`INSERT INTO slug_history (blog_id, slug, changed_at) VALUES (?, ?, ?)
+			ON CONFLICT (blog_id, slug) DO UPDATE SET changed_at = excluded.changed_at`,
+			existingBlog.ID.String(), previous.Slug, existingBlog.Updated.UnixNano())
+		if err != nil {
+			return nil, fmt.Errorf("failed to record slug history: %w", err)
+		}
+	}
+
+	// Record what the blog looked like before this update
+	if changed := models.ChangedFields(previous, *existingBlog); len(changed) > 0 {
+		changedData, err := json.Marshal(changed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal changed fields: %w", err)
+		}
+		previousData, err := json.Marshal(previous)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal revision: %w", err)
+		}
+		_, err = tx.Exec(`INSERT INTO blog_revisions (blog_id, revision_id, created, changed_fields, previous)
+			SELECT ?, COALESCE(MAX(revision_id), 0) + 1, ?, ?, ? FROM blog_revisions WHERE blog_id = ?`,
+			existingBlog.ID.String(), existingBlog.Updated.UnixNano(), string(changedData),
+			string(previousData), existingBlog.ID.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to record revision: %w", err)
+		}
+	}
+
+	return existingBlog, nil
+}
+
+func (s *SQLiteBlogStore) DeleteBlogBySlug(slug string) error {
+	// Deleting only marks the blog as trashed; images and revisions are
+	// removed by the ON DELETE CASCADE foreign keys once it is purged
+	result, err := s.db.Exec("UPDATE blogs SET deleted_at = ? WHERE slug = ? AND deleted_at IS NULL",
+		time.Now().UnixNano(), slug)
+	if err != nil {
+		return fmt.Errorf("failed to delete blog: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete blog: %w", err)
+	}
+	if affected == 0 {
+		return errors.New("blog not found")
+	}
+
+	return nil
+}
+
+// SaveBlogImage implements the BlogStore interface
+func (s *SQLiteBlogStore) SaveBlogImage(slug string, imageFilename string, imageData []byte) error {
+	result, err := s.db.Exec(`INSERT INTO blog_images (blog_id, filename, data)
+		SELECT id, ?, ? FROM blogs WHERE slug = ? AND deleted_at IS NULL
+		ON CONFLICT (blog_id, filename) DO UPDATE SET data = excluded.data`,
+		imageFilename, imageData, slug)
+	if err != nil {
+		return fmt.Errorf("failed to write image: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to write image: %w", err)
+	}
+	if affected == 0 {
+		return errors.New("blog not found")
+	}
+
+	return nil
+}
+
+// GetBlogImage implements the BlogStore interface
+func (s *SQLiteBlogStore) GetBlogImage(slug string, imageFilename string) ([]byte, error) {
+	var imageData []byte
+	err := s.db.QueryRow(`SELECT i.data FROM blog_images i
+		JOIN blogs b ON b.id = i.blog_id
+		WHERE b.slug = ? AND b.deleted_at IS NULL AND i.filename = ?`, slug, imageFilename).Scan(&imageData)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("image not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	return imageData, nil
+}
+
+// scanRevision reads a revision row selected as
+// revision_id, blog_id, created, changed_fields, previous
+func scanRevision(row rowScanner) (models.Revision, error) {
+	var revision models.Revision
+	var blogID, changedData, previousData string
+	var created int64
+
+	if err := row.Scan(&revision.ID, &blogID, &created, &changedData, &previousData); err != nil {
+		return models.Revision{}, err
+	}
+
+	var err error
+	revision.BlogID, err = uuid.Parse(blogID)
+	if err != nil {
+		return models.Revision{}, fmt.Errorf("invalid blog id %q: %w", blogID, err)
+	}
+	revision.Timestamp = time.Unix(0, created)
+	if err := json.Unmarshal([]byte(changedData), &revision.ChangedFields); err != nil {
+		return models.Revision{}, fmt.Errorf("invalid changed fields: %w", err)
+	}
+	if err := json.Unmarshal([]byte(previousData), &revision.Previous); err != nil {
+		return models.Revision{}, fmt.Errorf("invalid revision snapshot: %w", err)
+	}
+
+	return revision, nil
+}
+
+// getRevision loads a single revision using the given query runner (db or tx)
+func getRevision(q queryRower, slug string, revisionID int) (*models.Revision, error) {
+	blog, err := getBlogBySlug(q, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	row := q.QueryRow(`SELECT revision_id, blog_id, created, changed_fields, previous
+		FROM blog_revisions WHERE blog_id = ? AND revision_id = ?`, blog.ID.String(), revisionID)
+	revision, err := scanRevision(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("revision not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load revision: %w", err)
+	}
+
+	return &revision, nil
+}
+
+// ListRevisions implements the RevisionStore interface, newest first
+func (s *SQLiteBlogStore) ListRevisions(slug string) ([]models.Revision, error) {
+	blog, err := getBlogBySlug(s.db, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`SELECT revision_id, blog_id, created, changed_fields, previous
+		FROM blog_revisions WHERE blog_id = ? ORDER BY revision_id DESC`, blog.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []models.Revision{}
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, rows.Err()
+}
+
+// GetRevision implements the RevisionStore interface
+func (s *SQLiteBlogStore) GetRevision(slug string, revisionID int) (*models.Revision, error) {
+	return getRevision(s.db, slug, revisionID)
+}
+
+// RestoreRevision implements the RevisionStore interface. The restore is a
+// regular update, so the version it replaces becomes a new revision.
+func (s *SQLiteBlogStore) RestoreRevision(slug string, revisionID int) (*models.Blog, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	revision, err := getRevision(tx, slug, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := getBlogBySlug(tx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	restoredBlog, err := updateBlogBySlug(tx, slug, revision.RestoreRequest(current))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit restore: %w", err)
+	}
+
+	return restoredBlog, nil
+}
+
+// ListTrash implements the TrashStore interface, most recently deleted first
+func (s *SQLiteBlogStore) ListTrash() ([]models.TrashedBlog, error) {
+	rows, err := s.db.Query("SELECT " + blogColumns + `, deleted_at FROM blogs
+		WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trash: %w", err)
+	}
+	defer rows.Close()
+
+	trashed := []models.TrashedBlog{}
+	for rows.Next() {
+		var deletedAt int64
+		blog, err := scanBlog(trashRowScanner{rows, &deletedAt})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read trashed blog: %w", err)
+		}
+		trashed = append(trashed, models.TrashedBlog{Blog: blog, DeletedAt: time.Unix(0, deletedAt)})
+	}
+
+	return trashed, rows.Err()
+}
+
+// trashRowScanner lets scanBlog read rows that carry an extra deleted_at
+// column after blogColumns
+type trashRowScanner struct {
+	rows      rowScanner
+	deletedAt *int64
+}
+
+func (t trashRowScanner) Scan(dest ...interface{}) error {
+	return t.rows.Scan(append(dest, t.deletedAt)...)
+}
+
+// RestoreTrashedBlog implements the TrashStore interface. The blog returns
+// under the slug it was deleted with, which must not have been reused.
+func (s *SQLiteBlogStore) RestoreTrashedBlog(id uuid.UUID) (*models.Blog, error) {
+	result, err := s.db.Exec("UPDATE blogs SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL", id.String())
+	if isUniqueViolation(err) {
+		return nil, models.ErrSlugExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore blog: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore blog: %w", err)
+	}
+	if affected == 0 {
+		return nil, errors.New("blog not found in trash")
+	}
+
+	row := s.db.QueryRow("SELECT "+blogColumns+" FROM blogs WHERE id = ?", id.String())
+	blog, err := scanBlog(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load restored blog: %w", err)
+	}
+
+	return &blog, nil
+}
+
+// PurgeTrash implements the TrashStore interface, permanently removing
+// blogs deleted before the given time
+func (s *SQLiteBlogStore) PurgeTrash(deletedBefore time.Time) (int, error) {
+	result, err := s.db.Exec("DELETE FROM blogs WHERE deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore.UnixNano())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+
+	return int(affected), nil
+}
+
+// ResolveSlug implements the SlugHistoryStore interface. Every former slug of
+// a blog stays in slug_history, so chained renames resolve straight to the
+// current slug.
+func (s *SQLiteBlogStore) ResolveSlug(slug string) (*models.Blog, error) {
+	row := s.db.QueryRow(`SELECT `+blogColumns+` FROM blogs WHERE deleted_at IS NULL AND (slug = ? OR id = (
+		SELECT h.blog_id FROM slug_history h
+		JOIN blogs b ON b.id = h.blog_id
+		WHERE h.slug = ? AND b.deleted_at IS NULL
+		ORDER BY h.changed_at DESC LIMIT 1
+	)) ORDER BY slug = ? DESC LIMIT 1`, slug, slug, slug)
+	blog, err := scanBlog(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("blog not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve slug: %w", err)
+	}
+	return &blog, nil
+}
@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"go-react-backend/models"
+)
+
+// PurgeTrashPeriodically permanently removes blogs that have been in the
+// trash for longer than retention, checking once immediately and then every
+// interval until done is closed. Run it in its own goroutine.
+func PurgeTrashPeriodically(trash models.TrashStore, retention time.Duration, interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := trash.PurgeTrash(time.Now().Add(-retention))
+		if err != nil {
+			fmt.Printf("❌ Failed to purge trash: %v\n", err)
+		} else if purged > 0 {
+			fmt.Printf("🗑️ Purged %d blog(s) from the trash\n", purged)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/google/uuid"
+)
+
+// watchDebounce is how long the watcher waits for filesystem activity to
+// settle before rescanning, so an editor saving several files (or git
+// checking out a branch) results in one batch of events
+const watchDebounce = 250 * time.Millisecond
+
+// blogDirState captures what the watcher knows about one blog directory
+type blogDirState struct {
+	id           uuid.UUID
+	metadataMod  time.Time
+	metadataSize int64
+	contentMod   time.Time
+	contentSize  int64
+}
+
+// sameFiles reports whether both files are unchanged since other was taken
+func (s blogDirState) sameFiles(other blogDirState) bool {
+	return s.metadataMod.Equal(other.metadataMod) && s.metadataSize == other.metadataSize &&
+		s.contentMod.Equal(other.contentMod) && s.contentSize == other.contentSize
+}
+
+// Watcher watches a file storage data directory and publishes a ChangeEvent
+// whenever a blog directory is added, edited, renamed or removed on disk,
+// including changes made outside the application (editors, git checkouts)
+type Watcher struct {
+	dataDir  string
+	events   *EventBus
+	fsw      *fsnotify.Watcher
+	snapshot map[string]blogDirState // keyed by directory name (slug)
+	done     chan struct{}
+}
+
+// NewWatcher starts watching dataDir (inotify-based on Linux) and publishes
+// changes to events until Close is called
+func NewWatcher(dataDir string, events *EventBus) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	if err := fsw.Add(dataDir); err != nil {
+		fsw.Close()
+		return nil, fmt.Errorf("failed to watch data directory: %w", err)
+	}
+
+	w := &Watcher{
+		dataDir: dataDir,
+		events:  events,
+		fsw:     fsw,
+		done:    make(chan struct{}),
+	}
+
+	// Take the initial snapshot silently; only later changes produce events
+	w.snapshot = w.scan()
+
+	go w.run()
+
+	return w, nil
+}
+
+// Close stops the watcher
+func (w *Watcher) Close() error {
+	close(w.done)
+	return w.fsw.Close()
+}
+
+// run collects filesystem events and rescans once they settle
+func (w *Watcher) run() {
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+
+	for {
+		select {
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if isHiddenName(filepath.Base(event.Name)) {
+				continue // Temp files, trash and other internal state
+			}
+			timer.Reset(watchDebounce)
+
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			fmt.Printf("❌ File watcher error: %v\n", err)
+
+		case <-timer.C:
+			w.rescan()
+
+		case <-w.done:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// rescan compares the data directory with the last snapshot and publishes
+// the differences
+func (w *Watcher) rescan() {
+	current := w.scan()
+	previous := w.snapshot
+	w.snapshot = current
+
+	// Index the previous snapshot by blog ID to detect renamed directories
+	previousByID := make(map[uuid.UUID]string, len(previous))
+	for slug, state := range previous {
+		previousByID[state.id] = slug
+	}
+	seen := make(map[uuid.UUID]bool, len(current))
+
+	slugs := make([]string, 0, len(current))
+	for slug := range current {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+
+	for _, slug := range slugs {
+		state := current[slug]
+		seen[state.id] = true
+
+		oldSlug, existed := previousByID[state.id]
+		switch {
+		case !existed:
+			w.publish(ChangeEvent{Type: BlogCreated, ID: state.id, Slug: slug})
+		case oldSlug != slug:
+			w.publish(ChangeEvent{Type: BlogRenamed, ID: state.id, Slug: slug, OldSlug: oldSlug})
+		case !state.sameFiles(previous[slug]):
+			w.publish(ChangeEvent{Type: BlogUpdated, ID: state.id, Slug: slug})
+		}
+	}
+
+	for slug, state := range previous {
+		if !seen[state.id] {
+			w.publish(ChangeEvent{Type: BlogDeleted, ID: state.id, Slug: slug})
+		}
+	}
+}
+
+// publish logs and forwards an event to the bus
+func (w *Watcher) publish(event ChangeEvent) {
+	if event.Type == BlogRenamed {
+		fmt.Printf("👀 Blog %s on disk: %s -> %s\n", event.Type, event.OldSlug, event.Slug)
+	} else {
+		fmt.Printf("👀 Blog %s on disk: %s\n", event.Type, event.Slug)
+	}
+	w.events.Publish(event)
+}
+
+// scan reads the state of every blog directory, making sure each one is
+// watched. Metadata is only re-parsed for directories whose files changed.
+func (w *Watcher) scan() map[string]blogDirState {
+	entries, err := os.ReadDir(w.dataDir)
+	if err != nil {
+		fmt.Printf("❌ Failed to scan data directory %s: %v\n", w.dataDir, err)
+		return w.snapshot
+	}
+
+	states := make(map[string]blogDirState, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() || isHiddenName(entry.Name()) {
+			continue
+		}
+
+		blogDir := filepath.Join(w.dataDir, entry.Name())
+		metadataInfo, err := os.Stat(filepath.Join(blogDir, "metadata.json"))
+		if err != nil {
+			continue
+		}
+		contentInfo, err := os.Stat(filepath.Join(blogDir, "content.md"))
+		if err != nil {
+			continue
+		}
+
+		state := blogDirState{
+			metadataMod:  metadataInfo.ModTime(),
+			metadataSize: metadataInfo.Size(),
+			contentMod:   contentInfo.ModTime(),
+			contentSize:  contentInfo.Size(),
+		}
+
+		previous, known := w.snapshot[entry.Name()]
+		if known && previous.metadataMod.Equal(state.metadataMod) && previous.metadataSize == state.metadataSize {
+			state.id = previous.id
+		} else {
+			// Hand-edited metadata may be broken; the store skips such
+			// directories too
+			blog, err := loadBlogDir(blogDir)
+			if err != nil {
+				fmt.Printf("⚠️ Skipping blog directory %s: %v\n", entry.Name(), err)
+				continue
+			}
+			state.id = blog.ID
+		}
+
+		// Adding an already watched directory is a no-op
+		if err := w.fsw.Add(blogDir); err != nil {
+			fmt.Printf("❌ Failed to watch %s: %v\n", blogDir, err)
+		}
+
+		states[entry.Name()] = state
+	}
+
+	return states
+}
+
+// isHiddenName reports whether a file or directory name is internal to the
+// store (dotfiles such as temp files) rather than blog content
+func isHiddenName(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
@@ -245,7 +245,9 @@ func generateTypeScript(types []TypeInfo, outputDir string) error {
 	for _, t := range types {
 		if t.IsResponse || t.Name == "Blog" || 
 		   t.Name == "CreateBlogRequest" ||
-		   t.Name == "UpdateBlogRequest" {
+		   t.Name == "UpdateBlogRequest" ||
+		   t.Name == "ImageVariant" ||
+		   t.Name == "MediaItem" {
 			filteredTypes = append(filteredTypes, t)
 		}
 	}
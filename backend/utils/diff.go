@@ -0,0 +1,249 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffOp is one line of an edit script: ' ' keeps a line, '-' deletes it
+// from the old text and '+' inserts it from the new text
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// Limits on the texts UnifiedDiff is asked to compare, together, which keep
+// the time and memory a diff takes in check
+const (
+	MaxDiffLines = 10000
+	MaxDiffBytes = 1 << 20 // 1MB
+)
+
+// CheckDiffSize reports an error if oldText and newText are too large to diff
+func CheckDiffSize(oldText string, newText string) error {
+	if size := len(oldText) + len(newText); size > MaxDiffBytes {
+		return fmt.Errorf("texts too large to diff: %d bytes (max %d bytes)", size, MaxDiffBytes)
+	}
+	if lines := len(splitLines(oldText)) + len(splitLines(newText)); lines > MaxDiffLines {
+		return fmt.Errorf("texts too large to diff: %d lines (max %d lines)", lines, MaxDiffLines)
+	}
+	return nil
+}
+
+// UnifiedDiff returns a unified diff (as produced by `diff -u`) turning
+// oldText into newText, with the given number of context lines around each
+// change. It returns an empty string when the texts are identical.
+func UnifiedDiff(oldName string, newName string, oldText string, newText string, context int) string {
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	var out strings.Builder
+	for _, hunk := range diffHunks(ops, context) {
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+		}
+		out.WriteString(hunk)
+	}
+	return out.String()
+}
+
+// splitLines splits text into lines, ignoring the final newline
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines computes a shortest edit script from a to b using the linear
+// space variant of Myers' O((N+M)D) algorithm, which splits the problem at
+// the middle snake of a shortest path and solves each half in turn
+func diffLines(a []string, b []string) []diffOp {
+	return appendDiff(make([]diffOp, 0, len(a)+len(b)), a, b)
+}
+
+// appendDiff appends a shortest edit script from a to b to ops
+func appendDiff(ops []diffOp, a []string, b []string) []diffOp {
+	// Lines the texts start and end with are kept as they are
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{kind: ' ', line: line})
+	}
+	a, b = a[prefix:], b[prefix:]
+
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0:
+		for _, line := range b {
+			ops = append(ops, diffOp{kind: '+', line: line})
+		}
+	case len(b) == 0:
+		for _, line := range a {
+			ops = append(ops, diffOp{kind: '-', line: line})
+		}
+	default:
+		x, y, u, v := middleSnake(a, b)
+		ops = appendDiff(ops, a[:x], b[:y])
+		for _, line := range a[x:u] {
+			ops = append(ops, diffOp{kind: ' ', line: line})
+		}
+		ops = appendDiff(ops, a[u:], b[v:])
+	}
+
+	for _, line := range common {
+		ops = append(ops, diffOp{kind: ' ', line: line})
+	}
+	return ops
+}
+
+// middleSnake finds the middle snake of a shortest edit script from a to b,
+// searching forwards from the start and backwards from the end until the
+// two searches meet. It returns the snake's start (x, y) and end (u, v).
+func middleSnake(a []string, b []string) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+
+	// forward[k] is the furthest x reached on diagonal k = x - y from the
+	// start; backward[k] the same from the end, on the reversed texts
+	forward := make([]int, 2*maxD+3)
+	backward := make([]int, 2*maxD+3)
+
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1] // Move down: insertion
+			} else {
+				x = forward[offset+k-1] + 1 // Move right: deletion
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+
+			// The reversed diagonal delta - k holds the backward search
+			// after d - 1 steps
+			if odd && delta-k >= -(d-1) && delta-k <= d-1 && x+backward[offset+delta-k] >= n {
+				return startX, startY, x, y
+			}
+		}
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+
+			if !odd && delta-k >= -d && delta-k <= d && x+forward[offset+delta-k] >= n {
+				return n - x, m - y, n - startX, m - startY
+			}
+		}
+	}
+
+	// The searches always meet before here; should they not, deleting all of
+	// a and inserting all of b is still a valid, if long, edit script
+	return n, 0, n, 0
+}
+
+// diffHunks groups an edit script into formatted unified diff hunks
+func diffHunks(ops []diffOp, context int) []string {
+	var hunks []string
+
+	// Line numbers (0-based) in the old and new text at each op
+	oldLine := make([]int, len(ops)+1)
+	newLine := make([]int, len(ops)+1)
+	for i, op := range ops {
+		oldLine[i+1], newLine[i+1] = oldLine[i], newLine[i]
+		if op.kind != '+' {
+			oldLine[i+1]++
+		}
+		if op.kind != '-' {
+			newLine[i+1]++
+		}
+	}
+
+	i := 0
+	for i < len(ops) {
+		// Find the next change
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+
+		// Extend the hunk while changes are close enough to share context
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*context {
+				end += context
+				if end > len(ops) {
+					end = len(ops)
+				}
+				break
+			}
+			end = next
+		}
+
+		oldCount := oldLine[end] - oldLine[start]
+		newCount := newLine[end] - newLine[start]
+		oldStart := oldLine[start] + 1
+		newStart := newLine[start] + 1
+		// An empty range is reported as starting at the line before it
+		if oldCount == 0 {
+			oldStart--
+		}
+		if newCount == 0 {
+			newStart--
+		}
+
+		var hunk strings.Builder
+		fmt.Fprintf(&hunk, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		for _, op := range ops[start:end] {
+			hunk.WriteByte(op.kind)
+			hunk.WriteString(op.line)
+			hunk.WriteByte('\n')
+		}
+		hunks = append(hunks, hunk.String())
+
+		i = end
+	}
+
+	return hunks
+}
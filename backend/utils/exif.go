@@ -0,0 +1,234 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// JPEG markers used when reading and rewriting metadata
+const (
+	jpegSOI  = 0xD8 // Start of image
+	jpegEOI  = 0xD9 // End of image
+	jpegSOS  = 0xDA // Start of scan; entropy-coded data follows
+	jpegAPP0 = 0xE0 // JFIF header
+	jpegAPP1 = 0xE1 // EXIF or XMP
+	jpegAPP2 = 0xE2 // ICC profile, among others
+	jpegCOM  = 0xFE // Comment
+)
+
+// exifHeader starts the APP1 segment that holds EXIF data
+const exifHeader = "Exif\x00\x00"
+
+// iccHeader starts an APP2 segment that holds an ICC color profile
+const iccHeader = "ICC_PROFILE\x00"
+
+// exifOrientationTag is the EXIF tag saying how to turn the image upright
+const exifOrientationTag = 0x0112
+
+// jpegSegment is a marker segment of a JPEG file, before the image data
+type jpegSegment struct {
+	marker byte
+	data   []byte // Payload, without the marker and length
+}
+
+// readJPEGSegments splits a JPEG file into the marker segments before its
+// image data and the rest of the file, starting at the SOS marker
+func readJPEGSegments(data []byte) ([]jpegSegment, []byte, error) {
+	if len(data) < 2 || data[0] != 0xFF || data[1] != jpegSOI {
+		return nil, nil, errors.New("not a JPEG file")
+	}
+
+	var segments []jpegSegment
+	pos := 2
+	for {
+		// Markers may be preceded by any number of 0xFF fill bytes
+		if pos >= len(data) || data[pos] != 0xFF {
+			return nil, nil, errors.New("invalid JPEG marker")
+		}
+		for pos < len(data) && data[pos] == 0xFF {
+			pos++
+		}
+		if pos >= len(data) {
+			return nil, nil, errors.New("truncated JPEG file")
+		}
+		marker := data[pos]
+		if marker == jpegSOS || marker == jpegEOI {
+			return segments, data[pos-1:], nil
+		}
+		if pos+3 > len(data) {
+			return nil, nil, errors.New("truncated JPEG file")
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+1:]))
+		if length < 2 || pos+1+length > len(data) {
+			return nil, nil, errors.New("invalid JPEG segment length")
+		}
+		segments = append(segments, jpegSegment{marker: marker, data: data[pos+3 : pos+1+length]})
+		pos += 1 + length
+	}
+}
+
+// jpegOrientation returns the EXIF orientation (1-8) of a JPEG file, or 1,
+// meaning upright, if it has none or it can't be read
+func jpegOrientation(data []byte) int {
+	segments, _, err := readJPEGSegments(data)
+	if err != nil {
+		return 1
+	}
+	for _, segment := range segments {
+		if segment.marker == jpegAPP1 && bytes.HasPrefix(segment.data, []byte(exifHeader)) {
+			orientation, err := exifOrientation(segment.data[len(exifHeader):])
+			if err != nil {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
+
+// exifOrientation reads the orientation tag from the first IFD of EXIF data
+// (a TIFF structure), returning 1 if there is none
+func exifOrientation(tiff []byte) (int, error) {
+	if len(tiff) < 8 {
+		return 0, errors.New("truncated EXIF header")
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0, errors.New("invalid EXIF byte order")
+	}
+	if order.Uint16(tiff[2:]) != 42 {
+		return 0, errors.New("invalid EXIF header")
+	}
+
+	ifd := order.Uint32(tiff[4:])
+	if ifd < 8 || uint64(ifd)+2 > uint64(len(tiff)) {
+		return 0, errors.New("EXIF IFD offset out of range")
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := int(ifd) + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0, errors.New("truncated EXIF IFD")
+		}
+		if order.Uint16(tiff[entry:]) != exifOrientationTag {
+			continue
+		}
+		// A SHORT value is stored in the first two bytes of the value field
+		if order.Uint16(tiff[entry+2:]) != 3 {
+			return 0, errors.New("invalid EXIF orientation type")
+		}
+		orientation := int(order.Uint16(tiff[entry+8:]))
+		if orientation < 1 || orientation > 8 {
+			return 0, errors.New("invalid EXIF orientation")
+		}
+		return orientation, nil
+	}
+	return 1, nil
+}
+
+// orientImage turns img upright according to an EXIF orientation: 2-4 flip
+// or rotate it by 180 degrees, 5-8 also swap its width and height
+func orientImage(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	src := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // Mirrored
+				dx, dy = width-1-x, y
+			case 3: // Upside down
+				dx, dy = width-1-x, height-1-y
+			case 4: // Upside down and mirrored
+				dx, dy = x, height-1-y
+			case 5: // Transposed
+				dx, dy = y, x
+			case 6: // Needs turning clockwise
+				dx, dy = height-1-y, x
+			case 7: // Transversed
+				dx, dy = height-1-y, width-1-x
+			case 8: // Needs turning counterclockwise
+				dx, dy = y, width-1-x
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):][:4], src.Pix[src.PixOffset(x, y):][:4])
+		}
+	}
+
+	return dst
+}
+
+// stripJPEGMetadata returns a JPEG file without its EXIF, XMP, IPTC and
+// comment segments, leaving the image data untouched. The JFIF header and
+// ICC color profile are kept, and an orientation other than 1 is written
+// back as the only EXIF tag so the file still displays upright.
+func stripJPEGMetadata(data []byte, orientation int) ([]byte, error) {
+	segments, rest, err := readJPEGSegments(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var kept []jpegSegment
+	for _, segment := range segments {
+		isICC := segment.marker == jpegAPP2 && bytes.HasPrefix(segment.data, []byte(iccHeader))
+		isMetadata := (segment.marker >= jpegAPP1 && segment.marker <= 0xEF) || segment.marker == jpegCOM
+		if !isMetadata || isICC {
+			kept = append(kept, segment)
+		}
+	}
+
+	// EXIF goes first, or straight after the JFIF header
+	if orientation > 1 && orientation <= 8 {
+		at := 0
+		if len(kept) > 0 && kept[0].marker == jpegAPP0 {
+			at = 1
+		}
+		exif := jpegSegment{marker: jpegAPP1, data: orientationEXIF(orientation)}
+		kept = append(kept[:at], append([]jpegSegment{exif}, kept[at:]...)...)
+	}
+
+	stripped := []byte{0xFF, jpegSOI}
+	for _, segment := range kept {
+		stripped = append(stripped, 0xFF, segment.marker)
+		stripped = binary.BigEndian.AppendUint16(stripped, uint16(len(segment.data)+2))
+		stripped = append(stripped, segment.data...)
+	}
+	return append(stripped, rest...), nil
+}
+
+// orientationEXIF returns an EXIF segment payload holding only an
+// orientation tag
+func orientationEXIF(orientation int) []byte {
+	be := binary.BigEndian
+	data := []byte(exifHeader + "MM")
+	data = be.AppendUint16(data, 42)
+	data = be.AppendUint32(data, 8) // First IFD right after the header
+	data = be.AppendUint16(data, 1) // holding one entry,
+	data = be.AppendUint16(data, exifOrientationTag)
+	data = be.AppendUint16(data, 3) // a single SHORT
+	data = be.AppendUint32(data, 1)
+	data = be.AppendUint16(data, uint16(orientation))
+	data = be.AppendUint16(data, 0)
+	return be.AppendUint32(data, 0) // No further IFDs
+}
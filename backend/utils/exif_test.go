@@ -0,0 +1,296 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// byteOrder reads, writes and appends integers in one byte order
+type byteOrder interface {
+	binary.ByteOrder
+	binary.AppendByteOrder
+}
+
+// byteOrders are the two byte orders EXIF data can be written in
+var byteOrders = []struct {
+	name  string
+	order byteOrder
+}{
+	{"II", binary.LittleEndian},
+	{"MM", binary.BigEndian},
+}
+
+// Offsets into the EXIF data exifTIFF returns
+const (
+	testIFDOffset        = 4  // Offset of the first IFD
+	testOrientationEntry = 22 // The orientation tag's IFD entry
+	testOrientationEnd   = testOrientationEntry + 12
+	testOrientationType  = testOrientationEntry + 2
+	testOrientationValue = testOrientationEntry + 8
+)
+
+// exifTIFF returns EXIF data in the given byte order whose first IFD holds
+// a camera make and then an orientation tag
+func exifTIFF(name string, order byteOrder, orientation int) []byte {
+	data := []byte(name)
+	data = order.AppendUint16(data, 42)
+	data = order.AppendUint32(data, 8)
+	data = order.AppendUint16(data, 2)
+
+	data = order.AppendUint16(data, 0x010F) // Make,
+	data = order.AppendUint16(data, 2)      // ASCII,
+	data = order.AppendUint32(data, 4)      // four bytes, stored inline
+	data = append(data, "Cam\x00"...)
+
+	data = order.AppendUint16(data, exifOrientationTag)
+	data = order.AppendUint16(data, 3)
+	data = order.AppendUint32(data, 1)
+	data = order.AppendUint16(data, uint16(orientation))
+	data = order.AppendUint16(data, 0)
+
+	return order.AppendUint32(data, 0)
+}
+
+// testJPEG encodes a small image as a JPEG file with segments added
+// straight after its SOI marker
+func testJPEG(t *testing.T, segments ...jpegSegment) []byte {
+	t.Helper()
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 3, 2)), nil); err != nil {
+		t.Fatalf("jpeg.Encode: %v", err)
+	}
+
+	data := []byte{0xFF, jpegSOI}
+	for _, segment := range segments {
+		data = append(data, 0xFF, segment.marker)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(segment.data)+2))
+		data = append(data, segment.data...)
+	}
+	return append(data, encoded.Bytes()[2:]...)
+}
+
+// exifSegment returns an APP1 segment holding EXIF data
+func exifSegment(tiff []byte) jpegSegment {
+	return jpegSegment{marker: jpegAPP1, data: append([]byte(exifHeader), tiff...)}
+}
+
+func TestEXIFOrientation(t *testing.T) {
+	for _, bo := range byteOrders {
+		for orientation := 1; orientation <= 8; orientation++ {
+			tiff := exifTIFF(bo.name, bo.order, orientation)
+			got, err := exifOrientation(tiff)
+			if err != nil || got != orientation {
+				t.Errorf("%s: exifOrientation = %d, %v; want %d", bo.name, got, err, orientation)
+			}
+			if got := jpegOrientation(testJPEG(t, exifSegment(tiff))); got != orientation {
+				t.Errorf("%s: jpegOrientation = %d, want %d", bo.name, got, orientation)
+			}
+		}
+	}
+}
+
+func TestEXIFOrientationMissing(t *testing.T) {
+	for _, bo := range byteOrders {
+		tiff := exifTIFF(bo.name, bo.order, 6)
+		bo.order.PutUint16(tiff[testOrientationEntry:], 0x0110) // Now a Model tag
+		if got, err := exifOrientation(tiff); err != nil || got != 1 {
+			t.Errorf("%s: exifOrientation without the tag = %d, %v; want 1", bo.name, got, err)
+		}
+	}
+
+	if got := jpegOrientation(testJPEG(t)); got != 1 {
+		t.Errorf("jpegOrientation without EXIF = %d, want 1", got)
+	}
+}
+
+func TestEXIFOrientationMalformed(t *testing.T) {
+	for _, bo := range byteOrders {
+		valid := exifTIFF(bo.name, bo.order, 6)
+		tests := []struct {
+			name  string
+			patch func(tiff []byte) []byte
+		}{
+			{"byte order", func(tiff []byte) []byte { copy(tiff, "XX"); return tiff }},
+			{"magic number", func(tiff []byte) []byte { bo.order.PutUint16(tiff[2:], 43); return tiff }},
+			{"IFD inside the header", func(tiff []byte) []byte { bo.order.PutUint32(tiff[testIFDOffset:], 4); return tiff }},
+			{"IFD past the end", func(tiff []byte) []byte { bo.order.PutUint32(tiff[testIFDOffset:], uint32(len(tiff))); return tiff }},
+			{"IFD at the largest offset", func(tiff []byte) []byte { bo.order.PutUint32(tiff[testIFDOffset:], 0xFFFFFFFF); return tiff }},
+			{"more entries than data", func(tiff []byte) []byte { bo.order.PutUint16(tiff[8:], 0xFFFF); return tiff[:testOrientationEntry] }},
+			{"orientation as a LONG", func(tiff []byte) []byte { bo.order.PutUint16(tiff[testOrientationType:], 4); return tiff }},
+			{"orientation 0", func(tiff []byte) []byte { bo.order.PutUint16(tiff[testOrientationValue:], 0); return tiff }},
+			{"orientation 9", func(tiff []byte) []byte { bo.order.PutUint16(tiff[testOrientationValue:], 9); return tiff }},
+		}
+		for _, tt := range tests {
+			tiff := tt.patch(append([]byte(nil), valid...))
+			if got, err := exifOrientation(tiff); err == nil {
+				t.Errorf("%s %s: exifOrientation = %d, want an error", bo.name, tt.name, got)
+			}
+			if got := jpegOrientation(testJPEG(t, exifSegment(tiff))); got != 1 {
+				t.Errorf("%s %s: jpegOrientation = %d, want 1", bo.name, tt.name, got)
+			}
+		}
+
+		// Cut anywhere before the end of the orientation entry
+		for n := 0; n < testOrientationEnd; n++ {
+			if got, err := exifOrientation(valid[:n]); err == nil {
+				t.Errorf("%s cut to %d bytes: exifOrientation = %d, want an error", bo.name, n, got)
+			}
+		}
+	}
+}
+
+func TestReadJPEGSegmentsMalformed(t *testing.T) {
+	data := testJPEG(t, exifSegment(exifTIFF("MM", binary.BigEndian, 6)))
+	sos := bytes.Index(data, []byte{0xFF, jpegSOS})
+
+	// Cut anywhere before the image data
+	for n := 0; n < sos+2; n++ {
+		if _, _, err := readJPEGSegments(data[:n]); err == nil {
+			t.Errorf("readJPEGSegments of the first %d bytes succeeded, want an error", n)
+		}
+		if got := jpegOrientation(data[:n]); got != 1 {
+			t.Errorf("jpegOrientation of the first %d bytes = %d, want 1", n, got)
+		}
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"not a JPEG", []byte("\x89PNG\r\n\x1a\n")},
+		{"no marker after SOI", []byte{0xFF, jpegSOI, 0x00, jpegAPP0, 0x00, 0x02}},
+		{"segment length under 2", []byte{0xFF, jpegSOI, 0xFF, jpegAPP0, 0x00, 0x01, 0xFF, jpegEOI}},
+		{"segment past the end", []byte{0xFF, jpegSOI, 0xFF, jpegAPP0, 0xFF, 0xFF, 0x00}},
+	}
+	for _, tt := range tests {
+		if _, _, err := readJPEGSegments(tt.data); err == nil {
+			t.Errorf("%s: readJPEGSegments succeeded, want an error", tt.name)
+		}
+		if _, err := stripJPEGMetadata(tt.data, 1); err == nil {
+			t.Errorf("%s: stripJPEGMetadata succeeded, want an error", tt.name)
+		}
+	}
+}
+
+func TestOrientImage(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+
+	// A 3x2 image, red in its top left corner and blue in its top right
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	img.SetRGBA(0, 0, red)
+	img.SetRGBA(2, 0, blue)
+
+	// Where each orientation puts those corners, per the EXIF spec
+	tests := []struct {
+		orientation   int
+		width, height int
+		red, blue     image.Point
+	}{
+		{1, 3, 2, image.Pt(0, 0), image.Pt(2, 0)},
+		{2, 3, 2, image.Pt(2, 0), image.Pt(0, 0)},
+		{3, 3, 2, image.Pt(2, 1), image.Pt(0, 1)},
+		{4, 3, 2, image.Pt(0, 1), image.Pt(2, 1)},
+		{5, 2, 3, image.Pt(0, 0), image.Pt(0, 2)},
+		{6, 2, 3, image.Pt(1, 0), image.Pt(1, 2)},
+		{7, 2, 3, image.Pt(1, 2), image.Pt(1, 0)},
+		{8, 2, 3, image.Pt(0, 2), image.Pt(0, 0)},
+	}
+	for _, tt := range tests {
+		upright := orientImage(img, tt.orientation)
+		bounds := upright.Bounds()
+		if bounds.Dx() != tt.width || bounds.Dy() != tt.height {
+			t.Errorf("orientation %d: size %dx%d, want %dx%d", tt.orientation, bounds.Dx(), bounds.Dy(), tt.width, tt.height)
+			continue
+		}
+		if got := color.RGBAModel.Convert(upright.At(tt.red.X, tt.red.Y)); got != red {
+			t.Errorf("orientation %d: %v is %v, want red", tt.orientation, tt.red, got)
+		}
+		if got := color.RGBAModel.Convert(upright.At(tt.blue.X, tt.blue.Y)); got != blue {
+			t.Errorf("orientation %d: %v is %v, want blue", tt.orientation, tt.blue, got)
+		}
+	}
+}
+
+func TestDecodeImageOrientation(t *testing.T) {
+	for _, bo := range byteOrders {
+		data := testJPEG(t, exifSegment(exifTIFF(bo.name, bo.order, 6)))
+		img, format, err := decodeImage(data)
+		if err != nil {
+			t.Fatalf("decodeImage: %v", err)
+		}
+		if bounds := img.Bounds(); format != "jpeg" || bounds.Dx() != 2 || bounds.Dy() != 3 {
+			t.Errorf("%s: decoded a %dx%d %s, want a 2x3 jpeg", bo.name, bounds.Dx(), bounds.Dy(), format)
+		}
+	}
+}
+
+func TestStripJPEGMetadata(t *testing.T) {
+	jfif := jpegSegment{marker: jpegAPP0, data: []byte("JFIF\x00\x01\x01\x00\x00\x01\x00\x01\x00\x00")}
+	icc := jpegSegment{marker: jpegAPP2, data: []byte(iccHeader + "\x01\x01profile")}
+	data := testJPEG(t,
+		jfif,
+		exifSegment(exifTIFF("II", binary.LittleEndian, 6)),
+		jpegSegment{marker: jpegAPP1, data: []byte("http://ns.adobe.com/xap/1.0/\x00<gps>secret</gps>")},
+		icc,
+		jpegSegment{marker: jpegAPP2, data: []byte("MPF\x00secret")},
+		jpegSegment{marker: 0xED, data: []byte("Photoshop 3.0\x00secret")}, // IPTC
+		jpegSegment{marker: jpegCOM, data: []byte("secret comment")},
+	)
+	imageData := data[bytes.Index(data, []byte{0xFF, jpegSOS}):]
+
+	tests := []struct {
+		name        string
+		orientation int
+		want        []jpegSegment
+	}{
+		{"upright", 1, []jpegSegment{jfif, icc}},
+		{"turned", 6, []jpegSegment{jfif, {marker: jpegAPP1, data: orientationEXIF(6)}, icc}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stripped, err := stripJPEGMetadata(data, tt.orientation)
+			if err != nil {
+				t.Fatalf("stripJPEGMetadata: %v", err)
+			}
+			if bytes.Contains(stripped, []byte("secret")) || bytes.Contains(stripped, []byte("Cam")) {
+				t.Errorf("stripped file still holds metadata")
+			}
+			if !bytes.HasSuffix(stripped, imageData) {
+				t.Errorf("stripped file's image data changed")
+			}
+			if got := jpegOrientation(stripped); got != tt.orientation {
+				t.Errorf("jpegOrientation = %d, want %d", got, tt.orientation)
+			}
+
+			segments, _, err := readJPEGSegments(stripped)
+			if err != nil {
+				t.Fatalf("readJPEGSegments: %v", err)
+			}
+			// The encoder's own tables follow the kept segments
+			if len(segments) < len(tt.want) {
+				t.Fatalf("got %d segments, want at least %d", len(segments), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if segments[i].marker != want.marker || !bytes.Equal(segments[i].data, want.data) {
+					t.Errorf("segment %d = %X %q, want %X %q", i, segments[i].marker, segments[i].data, want.marker, want.data)
+				}
+			}
+
+			img, err := jpeg.Decode(bytes.NewReader(stripped))
+			if err != nil {
+				t.Fatalf("jpeg.Decode: %v", err)
+			}
+			if bounds := img.Bounds(); bounds.Dx() != 3 || bounds.Dy() != 2 {
+				t.Errorf("decoded size %dx%d, want 3x2", bounds.Dx(), bounds.Dy())
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"container/list"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cachedFile is an entry of an ImageCache
+type cachedFile struct {
+	name string
+	size int64
+}
+
+// ImageCache keeps generated images as files in a directory, deleting the
+// least recently used ones once their total size passes a limit. Files are
+// touched when read, so the order survives restarts.
+type ImageCache struct {
+	dir      string
+	maxBytes int64
+
+	mu      sync.Mutex
+	size    int64
+	order   *list.List // Most recently used first
+	entries map[string]*list.Element
+}
+
+// NewImageCache creates a cache of at most maxBytes in dir, picking up the
+// files already there
+func NewImageCache(dir string, maxBytes int64) (*ImageCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create image cache directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image cache directory: %w", err)
+	}
+
+	type existingFile struct {
+		cachedFile
+		used time.Time
+	}
+	var files []existingFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		// Dotfiles are writes interrupted by a crash
+		if strings.HasPrefix(entry.Name(), ".") {
+			os.Remove(filepath.Join(dir, entry.Name()))
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, existingFile{cachedFile{entry.Name(), info.Size()}, info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].used.After(files[j].used)
+	})
+
+	c := &ImageCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+	for _, file := range files {
+		c.entries[file.name] = c.order.PushBack(file.cachedFile)
+		c.size += file.size
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evict()
+
+	return c, nil
+}
+
+// Get returns the cached file called name, if there is one
+func (c *ImageCache) Get(name string) ([]byte, bool) {
+	c.mu.Lock()
+	element, ok := c.entries[name]
+	if ok {
+		c.order.MoveToFront(element)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	path := filepath.Join(c.dir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		// Evicted since, or removed behind the cache's back
+		c.mu.Lock()
+		if current, ok := c.entries[name]; ok && current == element {
+			c.remove(element)
+		}
+		c.mu.Unlock()
+		return nil, false
+	}
+
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	return data, true
+}
+
+// Put stores data in the cache as name, evicting other files as needed.
+// Files larger than the whole cache are not kept.
+func (c *ImageCache) Put(name string, data []byte) error {
+	if filepath.Base(name) != name || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid cache file name %q", name)
+	}
+	size := int64(len(data))
+	if size > c.maxBytes {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(c.dir, "."+name+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(c.dir, name))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[name]; ok {
+		c.size -= element.Value.(cachedFile).size
+		c.order.Remove(element)
+	}
+	c.entries[name] = c.order.PushFront(cachedFile{name, size})
+	c.size += size
+	c.evict()
+
+	return nil
+}
+
+// Size returns the number of files in the cache and their total size
+func (c *ImageCache) Size() (int, int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries), c.size
+}
+
+// evict deletes the least recently used files until the cache fits within
+// its limit. The caller must hold the lock.
+func (c *ImageCache) evict() {
+	for c.size > c.maxBytes {
+		element := c.order.Back()
+		if element == nil {
+			return
+		}
+		os.Remove(filepath.Join(c.dir, element.Value.(cachedFile).name))
+		c.remove(element)
+	}
+}
+
+// remove forgets a cache entry. The caller must hold the lock.
+func (c *ImageCache) remove(element *list.Element) {
+	file := element.Value.(cachedFile)
+	c.size -= file.size
+	c.order.Remove(element)
+	delete(c.entries, file.name)
+}
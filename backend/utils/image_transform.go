@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"fmt"
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// Fit modes for image transformations
+const (
+	FitContain = "contain" // Scale to fit within the box, keeping the aspect ratio
+	FitCover   = "cover"   // Scale to fill the box, cropping what overflows from the center
+	FitCrop    = "crop"    // Cut the box from the center without scaling
+)
+
+// MaxTransformDimension is the largest width or height a transformation may
+// ask for
+const MaxTransformDimension = 4000
+
+// TransformOptions describes a transformation of a stored image. A zero
+// Width or Height follows from the other and the image's aspect ratio.
+// Transformations never enlarge an image.
+type TransformOptions struct {
+	Width  int
+	Height int
+	Fit    string // One of the Fit constants
+	Format string // One of the Format constants
+}
+
+// WithDefaults fills in the fit mode and format when they are empty
+func (o TransformOptions) WithDefaults() TransformOptions {
+	if o.Fit == "" {
+		o.Fit = FitContain
+	}
+	if o.Format == "" {
+		o.Format = FormatAuto
+	}
+	return o
+}
+
+// Validate checks the box size, fit mode and format
+func (o TransformOptions) Validate() error {
+	if o.Width < 0 || o.Width > MaxTransformDimension || o.Height < 0 || o.Height > MaxTransformDimension {
+		return fmt.Errorf("width and height must be between 0 and %d", MaxTransformDimension)
+	}
+	if o.Width == 0 && o.Height == 0 {
+		return fmt.Errorf("width or height is required")
+	}
+	switch o.Fit {
+	case FitContain, FitCover, FitCrop:
+	default:
+		return fmt.Errorf("unsupported fit %q (use %s, %s or %s)", o.Fit, FitContain, FitCover, FitCrop)
+	}
+	switch o.Format {
+	case FormatAuto, FormatJPEG, FormatPNG:
+	default:
+		return fmt.Errorf("unsupported format %q (use %s, %s or %s)", o.Format, FormatAuto, FormatJPEG, FormatPNG)
+	}
+	return nil
+}
+
+// Query returns the options as URL query parameters in a fixed order, which
+// is also the form they are signed in
+func (o TransformOptions) Query() string {
+	return fmt.Sprintf("w=%d&h=%d&fit=%s&format=%s", o.Width, o.Height, o.Fit, o.Format)
+}
+
+// TransformImage decodes a stored image and applies opts to it, encoding the
+// result with quality if it is a JPEG
+func TransformImage(data []byte, opts TransformOptions, quality int) (ImageFile, error) {
+	img, _, err := decodeImage(data)
+	if err != nil {
+		return ImageFile{}, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := img.Bounds()
+	boxWidth, boxHeight := transformBox(bounds.Dx(), bounds.Dy(), opts.Width, opts.Height)
+
+	var transformed image.Image
+	switch opts.Fit {
+	case FitCover:
+		transformed = coverImage(img, boxWidth, boxHeight)
+	case FitCrop:
+		transformed = cropImage(img, boxWidth, boxHeight)
+	default:
+		transformed = img
+		if boxWidth < bounds.Dx() || boxHeight < bounds.Dy() {
+			transformed = resizeImage(img, boxWidth, boxHeight)
+		}
+	}
+
+	format := outputFormat(transformed, opts.Format)
+	return encodeImageFile(transformed, "transform."+formatExtension(format), format, quality)
+}
+
+// transformBox returns the box a width x height image is transformed into,
+// working out a missing side from the image's aspect ratio
+func transformBox(width, height, boxWidth, boxHeight int) (int, int) {
+	if boxWidth == 0 {
+		boxWidth = (width*boxHeight + height/2) / height
+	}
+	if boxHeight == 0 {
+		boxHeight = (height*boxWidth + width/2) / width
+	}
+	return max(boxWidth, 1), max(boxHeight, 1)
+}
+
+// coverImage crops the largest centered area with the box's aspect ratio
+// from img and scales it to fill the box. A box larger than that area is
+// shrunk to fit within it, keeping its shape.
+func coverImage(img image.Image, boxWidth, boxHeight int) image.Image {
+	bounds := img.Bounds()
+	cropWidth, cropHeight := calculateDimensions(boxWidth, boxHeight, bounds.Dx(), bounds.Dy())
+	cropWidth, cropHeight = max(cropWidth, 1), max(cropHeight, 1)
+	crop := centeredRect(bounds, cropWidth, cropHeight)
+
+	if boxWidth > cropWidth || boxHeight > cropHeight {
+		boxWidth, boxHeight = cropWidth, cropHeight
+	}
+
+	covered := image.NewRGBA(image.Rect(0, 0, boxWidth, boxHeight))
+	draw.CatmullRom.Scale(covered, covered.Bounds(), img, crop, draw.Over, nil)
+
+	return covered
+}
+
+// cropImage cuts a centered boxWidth x boxHeight area from img, or as much of
+// it as img has
+func cropImage(img image.Image, boxWidth, boxHeight int) image.Image {
+	bounds := img.Bounds()
+	crop := centeredRect(bounds, min(boxWidth, bounds.Dx()), min(boxHeight, bounds.Dy()))
+
+	cropped := image.NewRGBA(image.Rect(0, 0, crop.Dx(), crop.Dy()))
+	draw.Draw(cropped, cropped.Bounds(), img, crop.Min, draw.Src)
+
+	return cropped
+}
+
+// centeredRect returns a width x height rectangle centered in bounds
+func centeredRect(bounds image.Rectangle, width, height int) image.Rectangle {
+	origin := bounds.Min.Add(image.Pt((bounds.Dx()-width)/2, (bounds.Dy()-height)/2))
+	return image.Rectangle{Min: origin, Max: origin.Add(image.Pt(width, height))}
+}
+
+// formatExtension returns the file extension, without the dot, for images
+// encoded in format
+func formatExtension(format string) string {
+	if format == FormatJPEG {
+		return "jpg"
+	}
+	return format
+}
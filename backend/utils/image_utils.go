@@ -2,59 +2,332 @@ package utils
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"image"
-	_ "image/jpeg"
+	"image/color"
+	"image/jpeg"
 	"image/png"
 	"io"
+	"mime"
 	"mime/multipart"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Output formats for processed images
+const (
+	FormatAuto = "auto" // JPEG, or PNG for images with transparency
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
 )
 
 // ImageConfig holds configuration for image processing
 type ImageConfig struct {
 	MaxWidth    int
 	MaxHeight   int
+	VariantWidths []int  // Widths of the resized copies made for srcset
+	ThumbnailSize int    // Side of the square thumbnail
+	Format        string // One of the Format constants
+	Quality       int    // JPEG quality, 1-100
+	KeepOriginal  bool   // Also keep the upload, without its metadata
 }
 
 // DefaultImageConfig returns default configuration for blog images
 func DefaultImageConfig() ImageConfig {
 	return ImageConfig{
-		MaxWidth:  1200,
-		MaxHeight: 800,
+		MaxWidth:      1200,
+		MaxHeight:     800,
+		VariantWidths: []int{320, 640, 960, 1200, 2400},
+		ThumbnailSize: 300,
+		Format:        FormatAuto,
+		Quality:       82,
+	}
+}
+
+// Validate checks the output format and quality
+func (c ImageConfig) Validate() error {
+	switch c.Format {
+	case FormatAuto, FormatJPEG, FormatPNG:
+	default:
+		return fmt.Errorf("unsupported image format %q (use %s, %s or %s)", c.Format, FormatAuto, FormatJPEG, FormatPNG)
+	}
+	if c.Quality < 1 || c.Quality > 100 {
+		return fmt.Errorf("image quality must be between 1 and 100, got %d", c.Quality)
+	}
+	return nil
+}
+
+// ImageFile is an encoded image ready to be stored
+type ImageFile struct {
+	Filename string
+	MimeType string
+	Data     []byte
+	Width    int
+	Height   int
+}
+
+// ProcessedImage is an uploaded image with the copies made from it
+type ProcessedImage struct {
+	Hash      string      // SHA-256 of the uploaded file, hex encoded
+	Image     ImageFile   // Fitted within MaxWidth x MaxHeight
+	Variants  []ImageFile // One per variant width, narrowest first
+	Thumbnail ImageFile   // Square, cropped from the center
+	Original  *ImageFile  // The upload without its metadata, if kept
+
+	Placeholder string // Tiny PNG preview as a data URI
+	Color       string // Dominant color as #rrggbb
+}
+
+// Files returns the image, its variants, its thumbnail and the original if
+// it was kept
+func (p *ProcessedImage) Files() []ImageFile {
+	files := []ImageFile{p.Image}
+	files = append(files, p.Variants...)
+	files = append(files, p.Thumbnail)
+	if p.Original != nil {
+		files = append(files, *p.Original)
 	}
+	return files
 }
 
-// ProcessImage processes an uploaded image file and returns optimized image data
-func ProcessImage(file multipart.File, header *multipart.FileHeader, config ImageConfig) ([]byte, string, error) {
+// ProcessImage processes an uploaded image file into the optimized image,
+// its width variants and its thumbnail, all turned upright and without the
+// upload's metadata
+func ProcessImage(file multipart.File, header *multipart.FileHeader, config ImageConfig) (*ProcessedImage, error) {
 	// Read the file data
 	fileData, err := io.ReadAll(file)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to read file: %w", err)
+		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Decode the image
-	img, _, err := image.Decode(bytes.NewReader(fileData))
+	// Decode the image, applying any EXIF orientation
+	img, sourceFormat, err := decodeImage(fileData)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	// Every copy uses the same format, picked from the upload
+	format := outputFormat(img, config.Format)
+	ext := formatExtension(format)
+	encode := func(img image.Image, filename string) (ImageFile, error) {
+		return encodeImageFile(img, filename, format, config.Quality)
 	}
 
+	// Generate filename
+	filename := generateImageFilename(header.Filename, ext)
+
 	// Resize image if needed
-	resizedImg := resizeImage(img, config.MaxWidth, config.MaxHeight)
+	processed := ProcessedImage{Hash: hashImageData(fileData)}
+	processed.Image, err = encode(resizeImage(img, config.MaxWidth, config.MaxHeight), filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// Variants keep the aspect ratio and are never wider than the upload
+	bounds := img.Bounds()
+	for _, width := range variantWidths(bounds.Dx(), config.VariantWidths) {
+		variant, err := encode(resizeToWidth(img, width), variantFilename(filename, fmt.Sprintf("%dw", width)))
+		if err != nil {
+			return nil, err
+		}
+		processed.Variants = append(processed.Variants, variant)
+	}
 
-	// Convert to PNG
-	pngData, err := encodePNG(resizedImg)
+	processed.Thumbnail, err = encode(thumbnailImage(img, config.ThumbnailSize), variantFilename(filename, "thumb"))
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to encode PNG: %w", err)
+		return nil, err
 	}
 
-	// Generate filename
-	filename := generateImageFilename(header.Filename, "png")
+	// Pages show these while the image loads
+	processed.Placeholder, err = imagePlaceholder(img)
+	if err != nil {
+		return nil, err
+	}
+	processed.Color = dominantColor(img)
+
+	if config.KeepOriginal {
+		original, err := sanitizedOriginal(fileData, sourceFormat, img, filename)
+		if err != nil {
+			return nil, err
+		}
+		processed.Original = &original
+	}
 
-	return pngData, filename, nil
+	return &processed, nil
+}
+
+// decodeImage decodes an image file, turning JPEGs upright as their EXIF
+// orientation says. Encoding the result never writes metadata.
+func decodeImage(data []byte) (image.Image, string, error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	if format == "jpeg" {
+		img = orientImage(img, jpegOrientation(data))
+	}
+	return img, format, nil
+}
+
+// sanitizedOriginal returns the upload, decoded as img, without metadata for
+// keeping next to the image stored as filename. JPEGs keep their image data
+// byte for byte; other formats are saved as lossless PNGs of img.
+func sanitizedOriginal(data []byte, format string, img image.Image, filename string) (ImageFile, error) {
+	if format == "jpeg" {
+		stripped, err := stripJPEGMetadata(data, jpegOrientation(data))
+		if err == nil {
+			bounds := img.Bounds()
+			return ImageFile{
+				Filename: originalFilename(filename, "jpg"),
+				MimeType: "image/jpeg",
+				Data:     stripped,
+				Width:    bounds.Dx(),
+				Height:   bounds.Dy(),
+			}, nil
+		}
+	}
+	return encodeImageFile(img, originalFilename(filename, "png"), FormatPNG, 0)
+}
+
+// originalFilename names the kept original of the image stored as
+// filename, e.g. photo.jpg becomes photo-original.png for a PNG original
+func originalFilename(filename string, ext string) string {
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + "-original." + ext
+}
+
+// hashImageData returns the hex-encoded SHA-256 of an uploaded file, which
+// identifies it in the media library
+func hashImageData(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// outputFormat resolves the configured format for img: FormatAuto becomes
+// PNG if img has any transparency, since JPEG can't keep it, and JPEG
+// otherwise
+func outputFormat(img image.Image, configured string) string {
+	if configured != FormatAuto {
+		return configured
+	}
+	if isOpaque(img) {
+		return FormatJPEG
+	}
+	return FormatPNG
+}
+
+// isOpaque reports whether every pixel of img is fully opaque
+func isOpaque(img image.Image) bool {
+	// The standard image types (and WebP's) know without a full scan
+	if opaque, ok := img.(interface{ Opaque() bool }); ok {
+		return opaque.Opaque()
+	}
+
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a != 0xffff {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// encodeImageFile encodes img in format under filename
+func encodeImageFile(img image.Image, filename string, format string, quality int) (ImageFile, error) {
+	var data []byte
+	var err error
+	if format == FormatJPEG {
+		data, err = encodeJPEG(img, quality)
+	} else {
+		data, err = encodePNG(img)
+	}
+	if err != nil {
+		return ImageFile{}, fmt.Errorf("failed to encode %s: %w", strings.ToUpper(format), err)
+	}
+
+	bounds := img.Bounds()
+	return ImageFile{
+		Filename: filename,
+		MimeType: MimeTypeForFilename(filename),
+		Data:     data,
+		Width:    bounds.Dx(),
+		Height:   bounds.Dy(),
+	}, nil
+}
+
+// variantWidths returns the widths to make copies of an image width pixels
+// wide at: each configured width narrower than the image, then the image's
+// own width if it is narrower than the widest configured one
+func variantWidths(width int, configured []int) []int {
+	sorted := append([]int(nil), configured...)
+	sort.Ints(sorted)
+
+	var widths []int
+	for _, w := range sorted {
+		if w <= 0 || (len(widths) > 0 && widths[len(widths)-1] == w) {
+			continue
+		}
+		if w >= width {
+			return append(widths, width)
+		}
+		widths = append(widths, w)
+	}
+	return widths
+}
+
+// resizeToWidth scales img to exactly width pixels wide, keeping its aspect
+// ratio
+func resizeToWidth(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	if width == bounds.Dx() {
+		return img
+	}
+
+	height := (bounds.Dy()*width + bounds.Dx()/2) / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	resized := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(resized, resized.Bounds(), img, bounds, draw.Over, nil)
+
+	return resized
+}
+
+// thumbnailImage crops the largest centered square from img and scales it
+// down to size x size, or leaves it as is if it is smaller
+func thumbnailImage(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	if size > side {
+		size = side
+	}
+
+	origin := bounds.Min.Add(image.Pt((bounds.Dx()-side)/2, (bounds.Dy()-side)/2))
+	crop := image.Rectangle{Min: origin, Max: origin.Add(image.Pt(side, side))}
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(thumbnail, thumbnail.Bounds(), img, crop, draw.Over, nil)
+
+	return thumbnail
+}
+
+// variantFilename names a copy of the image stored as filename, e.g.
+// photo.png becomes photo-640w.png. Uploaded names never contain '-', so
+// copies can't collide with another upload.
+func variantFilename(filename string, suffix string) string {
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + "-" + suffix + ext
 }
 
 
@@ -113,6 +386,25 @@ func encodePNG(img image.Image) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// encodeJPEG encodes an image to JPEG format. Transparent areas, which JPEG
+// can't represent, are flattened onto white rather than turning black.
+func encodeJPEG(img image.Image, quality int) ([]byte, error) {
+	if !isOpaque(img) {
+		bounds := img.Bounds()
+		flattened := image.NewRGBA(bounds)
+		draw.Draw(flattened, bounds, image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flattened, bounds, img, bounds.Min, draw.Over)
+		img = flattened
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
 // generateImageFilename generates a unique filename for the image
 func generateImageFilename(originalFilename, newFormat string) string {
 	// Get file extension without dot
@@ -151,9 +443,13 @@ func cleanFilename(filename string) string {
 	return result.String()
 }
 
-// GetImageMimeType returns the MIME type for PNG images
-func GetImageMimeType() string {
-	return "image/png"
+// MimeTypeForFilename returns the MIME type of an image file from its
+// extension
+func MimeTypeForFilename(filename string) string {
+	if mimeType := mime.TypeByExtension(strings.ToLower(filepath.Ext(filename))); mimeType != "" {
+		return mimeType
+	}
+	return "application/octet-stream"
 }
 
 // ValidateImageFile validates an uploaded image file
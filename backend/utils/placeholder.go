@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"encoding/base64"
+	"fmt"
+	"image"
+)
+
+// PlaceholderSize is the longest side, in pixels, of placeholder previews
+const PlaceholderSize = 16
+
+// colorSampleSize is the longest side img is scaled down to before its
+// dominant color is worked out
+const colorSampleSize = 64
+
+// colorBucket gathers the pixels of an image falling in one coarse color
+type colorBucket struct {
+	count   int
+	r, g, b int
+}
+
+// imagePlaceholder returns a tiny PNG preview of img as a data URI, for pages
+// to show, blurred, until the image itself loads
+func imagePlaceholder(img image.Image) (string, error) {
+	data, err := encodePNG(shrinkImage(img, PlaceholderSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode placeholder: %w", err)
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// dominantColor returns the most common color of img as #rrggbb: the average
+// of the pixels in the most common of 4096 coarse colors. Mostly transparent
+// pixels don't count; an image with nothing else gets "".
+func dominantColor(img image.Image) string {
+	sample := shrinkImage(img, colorSampleSize)
+	bounds := sample.Bounds()
+
+	var buckets [4096]colorBucket
+	var best *colorBucket
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := sample.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+			// Undo the alpha premultiplication, down to 8 bits per channel
+			r, g, b = r*0xff/a, g*0xff/a, b*0xff/a
+
+			bucket := &buckets[(r>>4)<<8|(g>>4)<<4|b>>4]
+			bucket.count++
+			bucket.r += int(r)
+			bucket.g += int(g)
+			bucket.b += int(b)
+			if best == nil || bucket.count > best.count {
+				best = bucket
+			}
+		}
+	}
+
+	if best == nil {
+		return ""
+	}
+	return fmt.Sprintf("#%02x%02x%02x", best.r/best.count, best.g/best.count, best.b/best.count)
+}
+
+// shrinkImage scales img down to fit within size x size, or leaves it as is
+// if it already does
+func shrinkImage(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() <= size && bounds.Dy() <= size {
+		return img
+	}
+	// Unlike resizeImage, never rounds a very long, thin image down to
+	// nothing
+	width, _ := calculateDimensions(bounds.Dx(), bounds.Dy(), size, size)
+	return resizeToWidth(img, max(width, 1))
+}
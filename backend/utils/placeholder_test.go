@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// solidImage returns a width x height image filled with c
+func solidImage(width, height int, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	return img
+}
+
+func TestImagePlaceholder(t *testing.T) {
+	teal := color.NRGBA{0x33, 0x99, 0x99, 0xff}
+	tests := []struct {
+		name          string
+		img           image.Image
+		width, height int
+	}{
+		{"landscape", solidImage(200, 100, teal), 16, 8},
+		{"portrait", solidImage(90, 360, teal), 4, 16},
+		{"already small", solidImage(10, 5, teal), 10, 5},
+		{"long and thin", solidImage(2000, 10, teal), 16, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri, err := imagePlaceholder(tt.img)
+			if err != nil {
+				t.Fatalf("imagePlaceholder: %v", err)
+			}
+			encoded, ok := strings.CutPrefix(uri, "data:image/png;base64,")
+			if !ok {
+				t.Fatalf("placeholder %q is not a PNG data URI", uri)
+			}
+			data, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				t.Fatalf("decoding base64: %v", err)
+			}
+			preview, err := png.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("png.Decode: %v", err)
+			}
+
+			bounds := preview.Bounds()
+			if bounds.Dx() != tt.width || bounds.Dy() != tt.height {
+				t.Errorf("preview is %dx%d, want %dx%d", bounds.Dx(), bounds.Dy(), tt.width, tt.height)
+			}
+			center := color.NRGBAModel.Convert(preview.At(bounds.Dx()/2, bounds.Dy()/2))
+			if center != teal {
+				t.Errorf("preview center is %v, want %v", center, teal)
+			}
+		})
+	}
+}
+
+func TestDominantColor(t *testing.T) {
+	red := color.NRGBA{0xff, 0, 0, 0xff}
+	blue := color.NRGBA{0, 0, 0xff, 0xff}
+
+	// Mostly red, with a blue band on the right
+	mostlyRed := solidImage(100, 100, red)
+	draw.Draw(mostlyRed, image.Rect(70, 0, 100, 100), image.NewUniform(blue), image.Point{}, draw.Src)
+
+	// Transparent, but for a small green square. These are small enough not
+	// to be scaled down, which would blend edges with transparent pixels.
+	greenSquare := solidImage(60, 60, color.Transparent)
+	draw.Draw(greenSquare, image.Rect(20, 20, 40, 40), image.NewUniform(color.NRGBA{0, 0x80, 0, 0xff}), image.Point{}, draw.Src)
+
+	// Nearly transparent blue over a few opaque red pixels
+	faintBlue := solidImage(60, 60, color.NRGBA{0, 0, 0xff, 0x40})
+	draw.Draw(faintBlue, image.Rect(0, 0, 20, 20), image.NewUniform(red), image.Point{}, draw.Src)
+
+	// Two shades in one coarse color, averaged
+	shades := solidImage(10, 10, color.NRGBA{0x40, 0x40, 0x40, 0xff})
+	draw.Draw(shades, image.Rect(0, 0, 10, 5), image.NewUniform(color.NRGBA{0x42, 0x42, 0x42, 0xff}), image.Point{}, draw.Src)
+
+	tests := []struct {
+		name string
+		img  image.Image
+		want string
+	}{
+		{"solid", solidImage(300, 200, color.NRGBA{0x33, 0x66, 0x99, 0xff}), "#336699"},
+		{"most common", mostlyRed, "#ff0000"},
+		{"ignores transparent pixels", greenSquare, "#008000"},
+		{"ignores mostly transparent pixels", faintBlue, "#ff0000"},
+		{"averages a coarse color", shades, "#414141"},
+		{"fully transparent", solidImage(50, 50, color.Transparent), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dominantColor(tt.img); got != tt.want {
+				t.Errorf("dominantColor = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 import { Blog } from "@/types/generated";
 import { Image, Calendar, ArrowRight } from "lucide-react";
 import ReactMarkdown from "react-markdown";
+import { imageSrcSet } from "@/lib/images";
 
 const BlogCard = ({ blog }: { blog: Blog }) => {
   // Create a custom component to render plain text from markdown
@@ -26,6 +27,8 @@ const BlogCard = ({ blog }: { blog: Blog }) => {
           {blog.image ? (
             <img
               src={`/api/images/${blog.slug}/${blog.image}?v=${Date.now()}`}
+              srcSet={imageSrcSet(blog)}
+              sizes="(min-width: 1024px) 40vw, 100vw"
               alt={blog.title}
               className="w-full h-full object-cover"
               onError={(e) => {
@@ -3,6 +3,20 @@ import type { Blog, CreateBlogRequest } from "@/types";
 // Note: Read operations (fetchBlogs, getBlogBySlug) are no longer needed
 // as data is embedded directly in the HTML via server-side rendering
 
+// Thrown when a blog was changed by someone else since it was loaded
+export class BlogConflictError extends Error {
+  constructor() {
+    super(
+      "Someone else changed this blog since you opened it. Reload the page to see their changes before saving."
+    );
+    this.name = "BlogConflictError";
+  }
+}
+
+// Headers making a write conditional on the blog version the client has
+const ifMatchHeaders = (etag?: string | null): HeadersInit =>
+  etag ? { "If-Match": etag } : {};
+
 // Create a new blog (with optional image)
 export const createBlog = async (
   blogData: CreateBlogRequest,
@@ -42,11 +56,13 @@ export const createBlog = async (
   }
 };
 
-// Update an existing blog (with optional image)
+// Update an existing blog (with optional image). etag is the version the
+// edits were made against; the update fails if the blog changed since.
 export const updateBlog = async (
   slug: string,
   blogData: CreateBlogRequest,
-  imageFile?: File | null
+  imageFile?: File | null,
+  etag?: string | null
 ): Promise<Blog> => {
   try {
     // Always send as FormData - backend always expects multipart/form-data
@@ -67,9 +83,13 @@ export const updateBlog = async (
 
     const response = await fetch(`/api/blogs/${slug}`, {
       method: "PUT",
+      headers: ifMatchHeaders(etag),
       body: formData,
     });
 
+    if (response.status === 412) {
+      throw new BlogConflictError();
+    }
     if (!response.ok) {
       throw new Error("Failed to update blog");
     }
@@ -78,22 +98,35 @@ export const updateBlog = async (
     return result.data;
   } catch (error) {
     console.error("Failed to update blog:", error);
+    if (error instanceof BlogConflictError) {
+      throw error;
+    }
     throw new Error("Failed to update blog");
   }
 };
 
-// Delete a blog
-export const deleteBlog = async (slug: string): Promise<void> => {
+// Delete a blog, unless it changed since the etag version was loaded
+export const deleteBlog = async (
+  slug: string,
+  etag?: string | null
+): Promise<void> => {
   try {
     const response = await fetch(`/api/blogs/${slug}`, {
       method: "DELETE",
+      headers: ifMatchHeaders(etag),
     });
 
+    if (response.status === 412) {
+      throw new BlogConflictError();
+    }
     if (!response.ok) {
       throw new Error("Failed to delete blog");
     }
   } catch (error) {
     console.error("Failed to delete blog:", error);
+    if (error instanceof BlogConflictError) {
+      throw error;
+    }
     throw new Error("Failed to delete blog");
   }
 };
@@ -16,3 +16,13 @@ export function getEmbeddedSingleBlogData(): any | null {
   const data = (window as any).__BLOG_DATA__;
   return data || null;
 }
+
+// Version (ETag) of the embedded blog, sent back in If-Match when saving
+export function getEmbeddedBlogETag(): string | null {
+  if (typeof window === "undefined") {
+    return null; // Server-side rendering
+  }
+
+  const etag = (window as any).__BLOG_ETAG__;
+  return etag || null;
+}
@@ -0,0 +1,22 @@
+import type { Blog } from "@/types/generated";
+
+// URL of one of a blog's image files
+export const imageUrl = (slug: string, filename: string): string =>
+  `/api/images/${encodeURIComponent(slug)}/${encodeURIComponent(filename)}`;
+
+// srcset listing a blog's image variants, so the browser can pick the
+// smallest one that fits. Re-uploads keep their filenames, so the blog's
+// update time busts cached copies.
+export const imageSrcSet = (blog: Blog): string | undefined => {
+  if (!blog.image_variants || blog.image_variants.length === 0) {
+    return undefined;
+  }
+
+  const version = encodeURIComponent(blog.updated);
+  return blog.image_variants
+    .map(
+      (variant) =>
+        `${imageUrl(blog.slug, variant.filename)}?v=${version} ${variant.width}w`
+    )
+    .join(", ");
+};
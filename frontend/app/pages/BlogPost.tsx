@@ -37,12 +37,20 @@ const BlogPost = () => {
 
   const blog = embeddedData as Blog;
 
+  // Unpublished posts' images are only served with the page's preview token
+  const previewToken = new URLSearchParams(window.location.search).get(
+    "preview"
+  );
+  const previewQuery = previewToken
+    ? `&preview=${encodeURIComponent(previewToken)}`
+    : "";
+
   return (
     <Layout
       title={blog.title}
       backgroundImage={
         blog.image
-          ? `/api/images/${blog.slug}/${blog.image}?v=${Date.now()}`
+          ? `/api/images/${blog.slug}/${blog.image}?v=${Date.now()}${previewQuery}`
           : undefined
       }
     >
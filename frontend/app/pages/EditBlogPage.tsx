@@ -1,7 +1,14 @@
-import { getEmbeddedSingleBlogData } from "@/lib/embedded-data";
+import {
+  getEmbeddedBlogETag,
+  getEmbeddedSingleBlogData,
+} from "@/lib/embedded-data";
 import BlogForm from "@/components/BlogForm";
 import type { CreateBlogRequest, Blog } from "@/types/generated";
-import { updateBlog, deleteBlog } from "@/lib/api/services/blogs";
+import {
+  BlogConflictError,
+  updateBlog,
+  deleteBlog,
+} from "@/lib/api/services/blogs";
 
 const EditBlogPage = () => {
   // Get embedded data directly from server-side rendering
@@ -19,18 +26,27 @@ const EditBlogPage = () => {
   }
 
   const blog = embeddedData as Blog;
+  const etag = getEmbeddedBlogETag();
 
   const handleUpdateBlog = async (
     blogData: CreateBlogRequest,
     selectedImage?: File | null
   ) => {
     try {
-      const updatedBlog = await updateBlog(blog.slug, blogData, selectedImage);
+      const updatedBlog = await updateBlog(
+        blog.slug,
+        blogData,
+        selectedImage,
+        etag
+      );
 
       // Navigate back to the blog post (use new slug if it changed)
       window.location.href = `/blogs/${updatedBlog.slug}`;
     } catch (err) {
       console.error("Failed to update blog:", err);
+      if (err instanceof BlogConflictError) {
+        window.alert(err.message);
+      }
       throw err; // Re-throw to let the form handle the error
     }
   };
@@ -41,12 +57,15 @@ const EditBlogPage = () => {
 
   const handleDeleteBlog = async () => {
     try {
-      await deleteBlog(blog.slug);
+      await deleteBlog(blog.slug, etag);
 
       // Navigate to home page after successful deletion
       window.location.href = "/";
     } catch (error) {
       console.error("Failed to delete blog:", error);
+      if (error instanceof BlogConflictError) {
+        window.alert(error.message);
+      }
       throw error; // Re-throw to let the form handle the error
     }
   };
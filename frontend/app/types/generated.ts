@@ -9,14 +9,22 @@ export interface Blog {
   title: string;
   content: string;
   image: string;
+  image_variants: ImageVariant[];
+  image_thumbnail: string;
+  image_mime_type: string;
+  media: MediaItem[];
   author_name: string;
   author_username: string;
   meta_name: string;
   meta_description: string;
+  tags: string[];
+  category: string;
   slug: string;
   created: string;
   updated: string;
   published: boolean;
+  publish_at: string | null;
+  unpublish_at: string | null;
 }
 
 
@@ -29,8 +37,12 @@ export interface CreateBlogRequest {
   author_username: string;
   meta_name: string;
   meta_description: string;
+  tags: string[];
+  category: string;
   slug: string;
   published: boolean;
+  publish_at: string | null;
+  unpublish_at: string | null;
 }
 
 
@@ -39,12 +51,20 @@ export interface UpdateBlogRequest {
   title: string | null;
   content: string | null;
   image: string | null;
+  image_variants: ImageVariant[] | null;
+  image_thumbnail: string | null;
+  image_mime_type: string | null;
+  media: MediaItem[] | null;
   author_name: string | null;
   author_username: string | null;
   meta_name: string | null;
   meta_description: string | null;
+  tags: string[] | null;
+  category: string | null;
   slug: string | null;
   published: boolean | null;
+  publish_at: string | null;
+  unpublish_at: string | null;
 }
 
 
@@ -54,14 +74,126 @@ export interface BlogResponse {
   title: string;
   content: string;
   image: string;
+  image_variants: ImageVariant[];
+  image_thumbnail: string;
+  image_mime_type: string;
+  image_placeholder: string;
+  image_color: string;
+  media: MediaItemResponse[];
   author_name: string;
   author_username: string;
   meta_name: string;
   meta_description: string;
+  tags: string[];
+  category: string;
   slug: string;
   created: string;
   updated: string;
   published: boolean;
+  publish_at: string | null;
+  unpublish_at: string | null;
+}
+
+
+// BlogListResponse represents one page of a blog listing sent to clients
+export interface BlogListResponse {
+  blogs: BlogResponse[];
+  page: number;
+  per_page: number;
+  total: number;
+  total_pages: number;
+}
+
+
+// ImageVariant is a resized copy of a blog's image, stored next to it so
+pages can let browsers pick a size with srcset
+export interface ImageVariant {
+  filename: string;
+  width: number;
+  height: number;
+}
+
+
+// MediaItem is an image in a blog's media collection, either embedded in its
+content or used as its hero image (see Blog.Image). Its files are named
+for the blog; those of items with a Hash are stored in the media library.
+export interface MediaItem {
+  hash: string;
+  filename: string;
+  mime_type: string;
+  width: number;
+  height: number;
+  variants: ImageVariant[];
+  thumbnail: string;
+  alt_text: string;
+  caption: string;
+  created: string;
+  placeholder: string;
+  color: string;
+}
+
+
+// MediaItemResponse represents a media item sent to clients
+export interface MediaItemResponse {
+  hash: string;
+  filename: string;
+  url: string;
+  mime_type: string;
+  width: number;
+  height: number;
+  variants: ImageVariant[];
+  srcset: string;
+  thumbnail: string;
+  placeholder: string;
+  color: string;
+  alt_text: string;
+  caption: string;
+  hero: boolean;
+  created: string;
+}
+
+
+// MediaAssetResponse represents a library asset sent to clients
+export interface MediaAssetResponse {
+  hash: string;
+  url: string;
+  thumbnail_url: string;
+  original_url: string;
+  placeholder: string;
+  color: string;
+  mime_type: string;
+  width: number;
+  height: number;
+  variants: ImageVariant[];
+  size: number;
+  ref_count: number;
+  created: string;
+  uploaded: string;
+}
+
+
+// MediaUsageResponse represents a post's use of a library asset sent to
+clients
+export interface MediaUsageResponse {
+  blog_id: string;
+  slug: string;
+  title: string;
+  filename: string;
+  trashed: boolean;
+}
+
+
+// MediaGCResponse reports the result of a media garbage collection
+export interface MediaGCResponse {
+  deleted: number;
+}
+
+
+// PreviewLinkResponse represents a preview link sent to clients
+export interface PreviewLinkResponse {
+  url: string;
+  token: string;
+  expires_at: string;
 }
 
 
@@ -74,3 +206,89 @@ export interface Response {
 }
 
 
+// RevisionResponse represents a revision sent to clients
+export interface RevisionResponse {
+  id: number;
+  blog_id: string;
+  timestamp: string;
+  changed_fields: string[];
+  previous: BlogResponse | null;
+}
+
+
+// RevisionDiffResponse represents a unified diff between two versions of a blog
+export interface RevisionDiffResponse {
+  from: string;
+  to: string;
+  changed_fields: string[];
+  diff: string;
+}
+
+
+// SearchResultResponse represents a blog matching a search, sent to clients
+export interface SearchResultResponse {
+  id: string;
+  slug: string;
+  title: string;
+  meta_description: string;
+  image: string;
+  author_name: string;
+  tags: string[];
+  category: string;
+  created: string;
+  snippet: string;
+  score: number;
+}
+
+
+// SearchResponse represents one page of search results sent to clients
+export interface SearchResponse {
+  query: string;
+  results: SearchResultResponse[];
+  page: number;
+  per_page: number;
+  total: number;
+  total_pages: number;
+}
+
+
+// APITokenResponse represents the API token data sent to clients. Token is
+only set in the response to creating the token.
+export interface APITokenResponse {
+  id: string;
+  name: string;
+  prefix: string;
+  scopes: string[];
+  created: string;
+  last_used: string;
+  token: string;
+}
+
+
+// ImageTransformResponse represents a signed image transformation URL sent
+to clients
+export interface ImageTransformResponse {
+  url: string;
+  width: number;
+  height: number;
+  fit: string;
+  format: string;
+}
+
+
+// TrashedBlogResponse represents a trashed blog sent to clients
+export interface TrashedBlogResponse {
+  blog: BlogResponse;
+  deleted_at: string;
+}
+
+
+// UserResponse represents the user data sent to clients
+export interface UserResponse {
+  id: string;
+  username: string;
+  role: string;
+  created: string;
+}
+
+